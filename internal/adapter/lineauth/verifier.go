@@ -0,0 +1,82 @@
+package lineauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const verifyURL = "https://api.line.me/oauth2/v2.1/verify"
+
+// ErrInvalidIDToken is returned when LINE rejects an ID token.
+var ErrInvalidIDToken = errors.New("invalid LINE ID token")
+
+// Claims are the verified claims of a LINE ID token.
+type Claims struct {
+	UserID     string `json:"sub"`     // LINE user ID.
+	Name       string `json:"name"`    // Display name.
+	PictureURL string `json:"picture"` // Profile picture URL.
+}
+
+// IDTokenVerifier verifies the ID tokens that LIFF pages obtain with liff.getIDToken().
+type IDTokenVerifier struct {
+	channelID string
+	endpoint  string
+	client    *http.Client
+}
+
+// NewIDTokenVerifier creates a verifier accepting ID tokens issued for the LINE Login channel.
+func NewIDTokenVerifier(channelID string) *IDTokenVerifier {
+	return &IDTokenVerifier{
+		channelID: channelID,
+		endpoint:  verifyURL,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify asks LINE to verify the ID token and returns its claims.
+func (v *IDTokenVerifier) Verify(ctx context.Context, idToken string) (Claims, error) {
+	body := strings.NewReader(url.Values{
+		"id_token":  {idToken},
+		"client_id": {v.channelID},
+	}.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, body)
+	if err != nil {
+		return Claims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return Claims{}, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode == http.StatusBadRequest {
+		return Claims{}, ErrInvalidIDToken
+	}
+	if res.StatusCode != http.StatusOK {
+		return Claims{}, fmt.Errorf("verify ID token: unexpected status %d", res.StatusCode)
+	}
+	var claims Claims
+	if err := json.NewDecoder(res.Body).Decode(&claims); err != nil {
+		return Claims{}, err
+	}
+	if claims.UserID == "" {
+		return Claims{}, ErrInvalidIDToken
+	}
+	return claims, nil
+}
+
+// ChannelIDFromLiffID returns the LINE Login channel ID a LIFF ID belongs to, e.g. "1234567890" for "1234567890-AbcdEfgh".
+func ChannelIDFromLiffID(liffID string) string {
+	channelID, _, _ := strings.Cut(liffID, "-")
+	return channelID
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"werewolve-helper/internal/domain"
+)
+
+// FileRoundRepository stores every round in a single JSON file.
+// The whole file is rewritten on each change, which is fine for the handful of rooms a bot hosts.
+type FileRoundRepository struct {
+	mu     sync.Mutex
+	path   string
+	rounds map[string]*domain.Round // {key: ownerID, value: Round}
+}
+
+// NewFileRoundRepository opens the JSON file at path, creating it on the first save if missing.
+func NewFileRoundRepository(path string) (*FileRoundRepository, error) {
+	repo := &FileRoundRepository{
+		path:   path,
+		rounds: make(map[string]*domain.Round),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return repo, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &repo.rounds); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+// Save inserts or replaces the round keyed by its owner ID.
+func (repo *FileRoundRepository) Save(round *domain.Round) error {
+	// Copy through JSON so later changes by the caller are not written implicitly.
+	data, err := json.Marshal(round)
+	if err != nil {
+		return err
+	}
+	var r domain.Round
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.rounds[round.OwnerID] = &r
+	return repo.flushLocked()
+}
+
+// Delete removes the owner's round.
+func (repo *FileRoundRepository) Delete(ownerID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.rounds[ownerID]; !ok {
+		return nil
+	}
+	delete(repo.rounds, ownerID)
+	return repo.flushLocked()
+}
+
+// FindAll returns every stored round.
+func (repo *FileRoundRepository) FindAll() ([]*domain.Round, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	// Decode a fresh copy so callers cannot modify the cached rounds.
+	data, err := json.Marshal(repo.rounds)
+	if err != nil {
+		return nil, err
+	}
+	var copied map[string]*domain.Round
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+
+	rounds := make([]*domain.Round, 0, len(copied))
+	for _, r := range copied {
+		rounds = append(rounds, r)
+	}
+	return rounds, nil
+}
+
+// flushLocked atomically replaces the file with the current rounds. The caller must hold repo.mu.
+func (repo *FileRoundRepository) flushLocked() error {
+	data, err := json.MarshalIndent(repo.rounds, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(repo.path, data)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"encoding/json"
+	"sync"
+	"werewolve-helper/internal/domain"
+)
+
+// MemoryRoundRepository keeps rounds in process memory.
+// Rounds are stored as encoded snapshots, so callers never share state with the repository.
+type MemoryRoundRepository struct {
+	mu     sync.Mutex
+	rounds map[string][]byte // {key: ownerID, value: encoded Round}
+}
+
+// NewMemoryRoundRepository creates an empty MemoryRoundRepository.
+func NewMemoryRoundRepository() *MemoryRoundRepository {
+	return &MemoryRoundRepository{rounds: make(map[string][]byte)}
+}
+
+// Save inserts or replaces the round keyed by its owner ID.
+func (repo *MemoryRoundRepository) Save(round *domain.Round) error {
+	data, err := json.Marshal(round)
+	if err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.rounds[round.OwnerID] = data
+	return nil
+}
+
+// Delete removes the owner's round.
+func (repo *MemoryRoundRepository) Delete(ownerID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	delete(repo.rounds, ownerID)
+	return nil
+}
+
+// FindAll returns every stored round.
+func (repo *MemoryRoundRepository) FindAll() ([]*domain.Round, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	rounds := make([]*domain.Round, 0, len(repo.rounds))
+	for _, data := range repo.rounds {
+		var r domain.Round
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, &r)
+	}
+	return rounds, nil
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStoredRound() *domain.Round {
+	createdAt := time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC)
+	return &domain.Round{
+		OwnerID:   "owner1",
+		InviteNo:  "012345",
+		CreatedAt: createdAt,
+		ExpiredAt: createdAt.Add(2 * time.Hour),
+		Participants: []domain.Participant{
+			{UserID: "user1", Name: "User One", PictureURL: "url1", Identity: domain.Seer},
+			{UserID: "user2", Name: "User Two", PictureURL: "url2", Identity: domain.Werewolf},
+		},
+		Identities: []domain.Identity{domain.Seer, domain.Werewolf, domain.Villager},
+	}
+}
+
+func TestRoundRepositories(t *testing.T) {
+	backends := []struct {
+		name string
+		open func(t *testing.T, path string) usecase.RoundRepository
+		path string
+	}{
+		{
+			name: "memory",
+			open: func(_ *testing.T, _ string) usecase.RoundRepository { return NewMemoryRoundRepository() },
+		},
+		{
+			name: "file",
+			open: func(t *testing.T, path string) usecase.RoundRepository {
+				repo, err := NewFileRoundRepository(path)
+				require.NoError(t, err)
+				return repo
+			},
+			path: "rounds.json",
+		},
+		{
+			name: "sqlite",
+			open: func(t *testing.T, path string) usecase.RoundRepository {
+				repo, err := NewSQLiteRoundRepository(path)
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = repo.Close() })
+				return repo
+			},
+			path: "rounds.db",
+		},
+	}
+
+	for _, tt := range backends {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			path := filepath.Join(t.TempDir(), tt.path)
+			repo := tt.open(t, path)
+
+			rounds, err := repo.FindAll()
+			require.NoError(t, err)
+			assert.Empty(rounds, "New repository should be empty")
+
+			want := newStoredRound()
+			require.NoError(t, repo.Save(want))
+
+			// Modifying the saved round must not leak into the repository.
+			want.Participants[0].Name = "changed"
+			want = newStoredRound()
+
+			rounds, err = repo.FindAll()
+			require.NoError(t, err)
+			require.Len(t, rounds, 1)
+			assert.Equal(want, rounds[0], "Round should round-trip unchanged")
+
+			// Saving again replaces the round.
+			want.Participants = append(want.Participants, domain.Participant{UserID: "user3", Identity: domain.Villager})
+			require.NoError(t, repo.Save(want))
+			rounds, err = repo.FindAll()
+			require.NoError(t, err)
+			require.Len(t, rounds, 1)
+			assert.Len(rounds[0].Participants, 3)
+
+			require.NoError(t, repo.Delete("owner1"))
+			require.NoError(t, repo.Delete("owner1"), "Deleting a missing round should not fail")
+			rounds, err = repo.FindAll()
+			require.NoError(t, err)
+			assert.Empty(rounds)
+		})
+	}
+}
+
+func TestRoundRepositories_Reopen(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(dir, "rounds.json")
+		repo, err := NewFileRoundRepository(path)
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(newStoredRound()))
+
+		reopened, err := NewFileRoundRepository(path)
+		require.NoError(t, err)
+		rounds, err := reopened.FindAll()
+		require.NoError(t, err)
+		require.Len(t, rounds, 1)
+		assert.Equal(t, newStoredRound(), rounds[0])
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		path := filepath.Join(dir, "rounds.db")
+		repo, err := NewSQLiteRoundRepository(path)
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(newStoredRound()))
+		require.NoError(t, repo.Close())
+
+		reopened, err := NewSQLiteRoundRepository(path)
+		require.NoError(t, err)
+		defer func() { _ = reopened.Close() }()
+		rounds, err := reopened.FindAll()
+		require.NoError(t, err)
+		require.Len(t, rounds, 1)
+		assert.Equal(t, newStoredRound(), rounds[0])
+	})
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"werewolve-helper/internal/domain"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver, registered as "sqlite".
+)
+
+const createRoundsTable = `
+CREATE TABLE IF NOT EXISTS rounds (
+	owner_id   TEXT PRIMARY KEY,
+	invite_no  TEXT NOT NULL,
+	expired_at TIMESTAMP NOT NULL,
+	data       TEXT NOT NULL
+)`
+
+// SQLiteRoundRepository stores rounds in an embedded SQLite database.
+// The round itself is kept as a JSON document; owner, invite number and expiry are columns for inspection.
+type SQLiteRoundRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRoundRepository opens (or creates) the SQLite database at path.
+func NewSQLiteRoundRepository(path string) (*SQLiteRoundRepository, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer; one connection avoids "database is locked" errors.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(createRoundsTable); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &SQLiteRoundRepository{db: db}, nil
+}
+
+// Save inserts or replaces the round keyed by its owner ID.
+func (repo *SQLiteRoundRepository) Save(round *domain.Round) error {
+	data, err := json.Marshal(round)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.db.Exec(
+		`INSERT INTO rounds (owner_id, invite_no, expired_at, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT(owner_id) DO UPDATE SET invite_no = excluded.invite_no, expired_at = excluded.expired_at, data = excluded.data`,
+		round.OwnerID, round.InviteNo, round.ExpiredAt, string(data),
+	)
+	return err
+}
+
+// Delete removes the owner's round.
+func (repo *SQLiteRoundRepository) Delete(ownerID string) error {
+	_, err := repo.db.Exec(`DELETE FROM rounds WHERE owner_id = ?`, ownerID)
+	return err
+}
+
+// FindAll returns every stored round.
+func (repo *SQLiteRoundRepository) FindAll() ([]*domain.Round, error) {
+	rows, err := repo.db.Query(`SELECT data FROM rounds`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var rounds []*domain.Round
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var r domain.Round
+		if err := json.Unmarshal([]byte(data), &r); err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, &r)
+	}
+	return rounds, rows.Err()
+}
+
+// Close closes the underlying database.
+func (repo *SQLiteRoundRepository) Close() error {
+	return repo.db.Close()
+}
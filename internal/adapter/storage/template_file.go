@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"werewolve-helper/internal/domain"
+)
+
+// FileTemplateRepository stores every board template in a single JSON file.
+type FileTemplateRepository struct {
+	mu        sync.Mutex
+	path      string
+	templates map[string]map[string]*domain.BoardTemplate // {key: ownerID, value: {key: name, value: BoardTemplate}}
+}
+
+// NewFileTemplateRepository opens the JSON file at path, creating it on the first save if missing.
+func NewFileTemplateRepository(path string) (*FileTemplateRepository, error) {
+	repo := &FileTemplateRepository{
+		path:      path,
+		templates: make(map[string]map[string]*domain.BoardTemplate),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return repo, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &repo.templates); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+// Save inserts or replaces the template keyed by its owner ID and name.
+func (repo *FileTemplateRepository) Save(tmpl *domain.BoardTemplate) error {
+	// Copy so later changes by the caller are not written implicitly.
+	t := *tmpl
+	t.Identities = slices.Clone(tmpl.Identities)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.templates[t.OwnerID] == nil {
+		repo.templates[t.OwnerID] = make(map[string]*domain.BoardTemplate)
+	}
+	repo.templates[t.OwnerID][t.Name] = &t
+	return repo.flushLocked()
+}
+
+// Delete removes the owner's template with the given name.
+func (repo *FileTemplateRepository) Delete(ownerID, name string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.templates[ownerID][name]; !ok {
+		return nil
+	}
+	delete(repo.templates[ownerID], name)
+	if len(repo.templates[ownerID]) == 0 {
+		delete(repo.templates, ownerID)
+	}
+	return repo.flushLocked()
+}
+
+// FindByOwner returns the owner's templates sorted by name.
+func (repo *FileTemplateRepository) FindByOwner(ownerID string) ([]*domain.BoardTemplate, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	templates := make([]*domain.BoardTemplate, 0, len(repo.templates[ownerID]))
+	for _, tmpl := range repo.templates[ownerID] {
+		t := *tmpl
+		t.Identities = slices.Clone(tmpl.Identities)
+		templates = append(templates, &t)
+	}
+	sortTemplates(templates)
+	return templates, nil
+}
+
+// flushLocked atomically replaces the file with the current templates. The caller must hold repo.mu.
+func (repo *FileTemplateRepository) flushLocked() error {
+	data, err := json.MarshalIndent(repo.templates, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(repo.path, data)
+}
+
+// sortTemplates orders templates by name.
+func sortTemplates(templates []*domain.BoardTemplate) {
+	slices.SortFunc(templates, func(a, b *domain.BoardTemplate) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
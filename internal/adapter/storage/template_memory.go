@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"encoding/json"
+	"sync"
+	"werewolve-helper/internal/domain"
+)
+
+// MemoryTemplateRepository keeps board templates in process memory.
+// Templates are stored as encoded snapshots, so callers never share state with the repository.
+type MemoryTemplateRepository struct {
+	mu        sync.Mutex
+	templates map[string]map[string][]byte // {key: ownerID, value: {key: name, value: encoded BoardTemplate}}
+}
+
+// NewMemoryTemplateRepository creates an empty MemoryTemplateRepository.
+func NewMemoryTemplateRepository() *MemoryTemplateRepository {
+	return &MemoryTemplateRepository{templates: make(map[string]map[string][]byte)}
+}
+
+// Save inserts or replaces the template keyed by its owner ID and name.
+func (repo *MemoryTemplateRepository) Save(tmpl *domain.BoardTemplate) error {
+	data, err := json.Marshal(tmpl)
+	if err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.templates[tmpl.OwnerID] == nil {
+		repo.templates[tmpl.OwnerID] = make(map[string][]byte)
+	}
+	repo.templates[tmpl.OwnerID][tmpl.Name] = data
+	return nil
+}
+
+// Delete removes the owner's template with the given name.
+func (repo *MemoryTemplateRepository) Delete(ownerID, name string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	delete(repo.templates[ownerID], name)
+	return nil
+}
+
+// FindByOwner returns the owner's templates sorted by name.
+func (repo *MemoryTemplateRepository) FindByOwner(ownerID string) ([]*domain.BoardTemplate, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	templates := make([]*domain.BoardTemplate, 0, len(repo.templates[ownerID]))
+	for _, data := range repo.templates[ownerID] {
+		var t domain.BoardTemplate
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, err
+		}
+		templates = append(templates, &t)
+	}
+	sortTemplates(templates)
+	return templates, nil
+}
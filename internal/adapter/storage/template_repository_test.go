@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStoredTemplate(ownerID, name string) *domain.BoardTemplate {
+	return &domain.BoardTemplate{
+		OwnerID:    ownerID,
+		Name:       name,
+		Identities: []domain.Identity{domain.Werewolf, domain.Seer, domain.Villager},
+		Rules:      domain.Rules{TieRule: domain.TieNoExile, WinRule: domain.WinAllKill},
+		UpdatedAt:  time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestTemplateRepositories(t *testing.T) {
+	backends := []struct {
+		name string
+		open func(t *testing.T, path string) usecase.TemplateRepository
+		path string
+	}{
+		{
+			name: "memory",
+			open: func(_ *testing.T, _ string) usecase.TemplateRepository { return NewMemoryTemplateRepository() },
+		},
+		{
+			name: "file",
+			open: func(t *testing.T, path string) usecase.TemplateRepository {
+				repo, err := NewFileTemplateRepository(path)
+				require.NoError(t, err)
+				return repo
+			},
+			path: "templates.json",
+		},
+		{
+			name: "sqlite",
+			open: func(t *testing.T, path string) usecase.TemplateRepository {
+				repo, err := NewSQLiteTemplateRepository(path)
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = repo.Close() })
+				return repo
+			},
+			path: "templates.db",
+		},
+	}
+
+	for _, tt := range backends {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			repo := tt.open(t, filepath.Join(t.TempDir(), tt.path))
+
+			templates, err := repo.FindByOwner("owner1")
+			require.NoError(t, err)
+			assert.Empty(templates, "New repository should be empty")
+
+			want := newStoredTemplate("owner1", "週五團")
+			require.NoError(t, repo.Save(want))
+			want.Identities[0] = domain.Villager
+			require.NoError(t, repo.Save(newStoredTemplate("owner1", "A board")))
+			require.NoError(t, repo.Save(newStoredTemplate("owner2", "other")))
+
+			templates, err = repo.FindByOwner("owner1")
+			require.NoError(t, err)
+			require.Len(t, templates, 2, "Templates are kept per owner")
+			assert.Equal("A board", templates[0].Name, "Templates are sorted by name")
+			assert.Equal(newStoredTemplate("owner1", "週五團"), templates[1], "Template should round-trip unchanged")
+
+			// Saving under the same name replaces the template.
+			replaced := newStoredTemplate("owner1", "週五團")
+			replaced.Identities = append(replaced.Identities, domain.Hunter)
+			require.NoError(t, repo.Save(replaced))
+			templates, err = repo.FindByOwner("owner1")
+			require.NoError(t, err)
+			require.Len(t, templates, 2)
+			assert.Len(templates[1].Identities, 4)
+
+			require.NoError(t, repo.Delete("owner1", "週五團"))
+			require.NoError(t, repo.Delete("owner1", "週五團"), "Deleting a missing template should not fail")
+			templates, err = repo.FindByOwner("owner1")
+			require.NoError(t, err)
+			require.Len(t, templates, 1)
+			assert.Equal("A board", templates[0].Name)
+		})
+	}
+}
+
+func TestTemplateRepositories_Reopen(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(dir, "templates.json")
+		repo, err := NewFileTemplateRepository(path)
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(newStoredTemplate("owner1", "board")))
+
+		reopened, err := NewFileTemplateRepository(path)
+		require.NoError(t, err)
+		templates, err := reopened.FindByOwner("owner1")
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		assert.Equal(t, newStoredTemplate("owner1", "board"), templates[0])
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		path := filepath.Join(dir, "templates.db")
+		repo, err := NewSQLiteTemplateRepository(path)
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(newStoredTemplate("owner1", "board")))
+		require.NoError(t, repo.Close())
+
+		reopened, err := NewSQLiteTemplateRepository(path)
+		require.NoError(t, err)
+		defer func() { _ = reopened.Close() }()
+		templates, err := reopened.FindByOwner("owner1")
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		assert.Equal(t, newStoredTemplate("owner1", "board"), templates[0])
+	})
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"werewolve-helper/internal/domain"
+)
+
+const createTemplatesTable = `
+CREATE TABLE IF NOT EXISTS templates (
+	owner_id   TEXT NOT NULL,
+	name       TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	data       TEXT NOT NULL,
+	PRIMARY KEY (owner_id, name)
+)`
+
+// SQLiteTemplateRepository stores board templates in an embedded SQLite database.
+type SQLiteTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteTemplateRepository opens (or creates) the SQLite database at path.
+func NewSQLiteTemplateRepository(path string) (*SQLiteTemplateRepository, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer; one connection avoids "database is locked" errors.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(createTemplatesTable); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &SQLiteTemplateRepository{db: db}, nil
+}
+
+// Save inserts or replaces the template keyed by its owner ID and name.
+func (repo *SQLiteTemplateRepository) Save(tmpl *domain.BoardTemplate) error {
+	data, err := json.Marshal(tmpl)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.db.Exec(
+		`INSERT INTO templates (owner_id, name, updated_at, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT(owner_id, name) DO UPDATE SET updated_at = excluded.updated_at, data = excluded.data`,
+		tmpl.OwnerID, tmpl.Name, tmpl.UpdatedAt, string(data),
+	)
+	return err
+}
+
+// Delete removes the owner's template with the given name.
+func (repo *SQLiteTemplateRepository) Delete(ownerID, name string) error {
+	_, err := repo.db.Exec(`DELETE FROM templates WHERE owner_id = ? AND name = ?`, ownerID, name)
+	return err
+}
+
+// FindByOwner returns the owner's templates sorted by name.
+func (repo *SQLiteTemplateRepository) FindByOwner(ownerID string) ([]*domain.BoardTemplate, error) {
+	rows, err := repo.db.Query(`SELECT data FROM templates WHERE owner_id = ?`, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var templates []*domain.BoardTemplate
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var t domain.BoardTemplate
+		if err := json.Unmarshal([]byte(data), &t); err != nil {
+			return nil, err
+		}
+		templates = append(templates, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Sort in Go rather than with ORDER BY, so every backend orders names the same way.
+	sortTemplates(templates)
+	return templates, nil
+}
+
+// Close closes the underlying database.
+func (repo *SQLiteTemplateRepository) Close() error {
+	return repo.db.Close()
+}
@@ -1,12 +1,24 @@
 package internal
 
+import "time"
+
 type BotConfig struct {
-	LineChannelSecret string
-	LineChannelToken  string
-	Port              string
-	DiscordBotToken   string
-	DiscordChannelID  string
-	LiffID            string
+	LineChannelSecret   string
+	LineChannelToken    string
+	Port                string
+	DiscordBotToken     string
+	DiscordChannelID    string
+	LiffID              string
+	LiffRoomID          string        // LIFF app of the room dashboard, empty to hide the dashboard link.
+	RoundStorage        string        // Round storage backend: "memory", "file" or "sqlite".
+	RoundStoragePath    string        // Path of the round storage file for "file" and "sqlite".
+	JanitorInterval     time.Duration // Interval between sweeps of expired rounds.
+	TemplateStoragePath string        // Path of the template storage file for "file" and "sqlite".
+	LineLoginChannelID  string        // LINE Login channel of the LIFF app, used to verify ID tokens.
+	LineBotBasicID      string        // Basic ID of the bot, e.g. "@267acwzx", used in invite deep links.
+	InviteNoFormat      string        // Format of new invite numbers: "digits", "words" or "base32".
+	InviteNoLength      int           // Length of base32 invite numbers, 7 to 16; zero picks the default.
+	MetricsToken        string        // Bearer token guarding the metrics endpoints, empty to leave them out.
 
 	// DeveloperID     string // Deprecated: developer ID is not used
 	// LineNotifyToken string // Deprecated: LINE Notify token is not used
@@ -0,0 +1,74 @@
+package domain
+
+import "strconv"
+
+// balancedMargin is the largest score, either way, of a board considered balanced.
+const balancedMargin = 5
+
+// Advantage tells which side a board favours.
+type Advantage int
+
+// Constants for the board advantages.
+const (
+	AdvantageBalanced Advantage = iota + 1 // Neither side is clearly favoured.
+	AdvantageGood                          // The good side is favoured.
+	AdvantageWolves                        // The wolves are favoured.
+)
+
+// String returns the string representation of an Advantage.
+func (a Advantage) String() string {
+	switch a {
+	case AdvantageBalanced:
+		return "平衡"
+	case AdvantageGood:
+		return "好人優勢"
+	case AdvantageWolves:
+		return "狼人優勢"
+	default:
+		return "unknown"
+	}
+}
+
+// Balance is the estimated balance of a board, computed from the per-role weights of the registry.
+type Balance struct {
+	GoodPower int       // Sum of the positive weights.
+	WolfPower int       // Sum of the negative weights, as a positive number.
+	Score     int       // GoodPower - WolfPower; positive favours the good side.
+	Advantage Advantage // Side the score favours.
+}
+
+// ScoreBoard estimates the balance of a board given as role counts. Unknown identities weigh nothing.
+func ScoreBoard(counts map[Identity]int) Balance {
+	var b Balance
+	for _, r := range roles {
+		n := counts[r.Identity]
+		if n <= 0 {
+			continue
+		}
+		if r.Weight > 0 {
+			b.GoodPower += r.Weight * n
+		} else {
+			b.WolfPower -= r.Weight * n
+		}
+	}
+
+	b.Score = b.GoodPower - b.WolfPower
+	switch {
+	case b.Score > balancedMargin:
+		b.Advantage = AdvantageGood
+	case b.Score < -balancedMargin:
+		b.Advantage = AdvantageWolves
+	default:
+		b.Advantage = AdvantageBalanced
+	}
+	return b
+}
+
+// String describes the balance, e.g. "平衡 (+2)".
+func (b Balance) String() string {
+	score := strconv.Itoa(b.Score)
+	if b.Score > 0 {
+		score = "+" + score
+	}
+	return b.Advantage.String() + " (" + score + ")"
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreBoard_Presets(t *testing.T) {
+	tests := []struct {
+		key       string
+		score     int
+		advantage Advantage
+	}{
+		{"9-standard", 2, AdvantageBalanced},
+		{"10-idiot", 5, AdvantageBalanced},
+		{"12-idiot", 0, AdvantageBalanced},
+		{"12-guard", 1, AdvantageBalanced},
+		{"12-wolfking", -1, AdvantageBalanced},
+		{"12-whitewolf", 0, AdvantageBalanced},
+		{"12-beauty", 0, AdvantageBalanced},
+	}
+
+	require.Len(t, tests, len(Presets()), "Every preset should be scored")
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			p, err := LookupPreset(tt.key)
+			require.NoError(t, err)
+
+			b := ScoreBoard(p.Counts)
+			assert.Equal(t, tt.score, b.Score)
+			assert.Equal(t, tt.advantage, b.Advantage)
+			assert.Equal(t, b.GoodPower-b.WolfPower, b.Score)
+		})
+	}
+}
+
+func TestScoreBoard_Lopsided(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[Identity]int
+		want   Balance
+	}{
+		{
+			"4 wolves against 1 god",
+			map[Identity]int{Werewolf: 4, Seer: 1, Villager: 4},
+			Balance{GoodPower: 11, WolfPower: 24, Score: -13, Advantage: AdvantageWolves},
+		},
+		{
+			"1 wolf against every god",
+			map[Identity]int{Werewolf: 1, Seer: 1, Witch: 1, Hunter: 1, Guard: 1, Villager: 2},
+			Balance{GoodPower: 23, WolfPower: 6, Score: 17, Advantage: AdvantageGood},
+		},
+		{
+			"unknown and negative counts are ignored",
+			map[Identity]int{Werewolf: 1, Villager: 6, Seer: -1, Identity(99): 3},
+			Balance{GoodPower: 6, WolfPower: 6, Score: 0, Advantage: AdvantageBalanced},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ScoreBoard(tt.counts), tt.name)
+	}
+}
+
+func TestBalance_String(t *testing.T) {
+	assert.Equal(t, "平衡 (+2)", Balance{Score: 2, Advantage: AdvantageBalanced}.String())
+	assert.Equal(t, "狼人優勢 (-13)", Balance{Score: -13, Advantage: AdvantageWolves}.String())
+	assert.Equal(t, "平衡 (0)", Balance{Advantage: AdvantageBalanced}.String())
+}
@@ -0,0 +1,56 @@
+package domain
+
+// deathCause is how a player died, which decides whether death abilities trigger.
+type deathCause int
+
+// Constants for the causes of death.
+const (
+	deathWolves     deathCause = iota + 1 // Killed by the wolves.
+	deathPoison                           // Poisoned by the Witch.
+	deathExile                            // Exiled by the day vote.
+	deathShot                             // Shot by the Hunter.
+	deathHeartbreak                       // Died with their lover.
+)
+
+// kill marks the player as dead and applies what their death sets off:
+// the lover dies of heartbreak, the Wild Child turns wolf when the role model dies,
+// and a Hunter who was not poisoned or heartbroken gets to shoot.
+// It returns everyone who died, the player first, and the private notices it produced.
+func (g *Game) kill(userID string, cause deathCause) ([]string, []Notice) {
+	p, ok := g.Player(userID)
+	if !ok || !p.Alive {
+		return nil, nil
+	}
+	p.Alive = false
+	dead := []string{userID}
+	var notices []Notice
+
+	if p.Identity == Hunter && cause != deathPoison && cause != deathHeartbreak && g.PendingShooter == "" {
+		g.PendingShooter = userID
+	}
+	if userID == g.RoleModel {
+		for i := range g.Players {
+			c := &g.Players[i]
+			if c.Identity == WildChild && c.Alive && c.camp() == FactionVillager {
+				c.Faction = FactionWolf
+				notices = append(notices, Notice{To: c.UserID, Text: "你的榜樣 " + p.Label() + " 已死亡，你成為狼人，今晚起與狼人一起行動"})
+			}
+		}
+	}
+	if p.Lover != "" {
+		ids, ns := g.kill(p.Lover, deathHeartbreak)
+		dead = append(dead, ids...)
+		notices = append(notices, ns...)
+	}
+	return dead, notices
+}
+
+// deathNotices announces the players who died along with a daytime death, e.g. a heartbroken lover,
+// followed by the private notices of the deaths.
+func (g *Game) deathNotices(dead []string, private []Notice) []Notice {
+	var notices []Notice
+	if len(dead) > 1 {
+		notices = append(notices, Notice{Text: g.names(dead[1:]) + " 殉情"})
+	}
+	return append(notices, private...)
+}
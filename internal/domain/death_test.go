@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_KillLoversAndHunter(t *testing.T) {
+	g := newBoardGame(t, Werewolf, Hunter, Villager, Villager)
+	g.Players[1].Lover, g.Players[2].Lover = "p2", "p1"
+	assert := assert.New(t)
+
+	dead, notices := g.kill("p2", deathExile)
+	assert.Equal([]string{"p2", "p1"}, dead)
+	assert.Empty(notices)
+	assert.Empty(g.PendingShooter, "A heartbroken Hunter cannot shoot")
+
+	dead, _ = g.kill("p2", deathExile)
+	assert.Nil(dead, "The dead cannot die again")
+	assert.Equal([]Notice{{Text: "P1 殉情"}}, g.deathNotices([]string{"p2", "p1"}, nil))
+}
+
+func TestGame_KillHunterShoots(t *testing.T) {
+	g := newBoardGame(t, Werewolf, Hunter, Villager, Villager)
+
+	g.kill("p1", deathPoison)
+	assert.Empty(t, g.PendingShooter, "A poisoned Hunter cannot shoot")
+
+	g = newBoardGame(t, Werewolf, Hunter, Villager, Villager)
+	g.kill("p1", deathWolves)
+	assert.Equal(t, "p1", g.PendingShooter)
+}
@@ -0,0 +1,33 @@
+package domain
+
+// Faction represents the team an identity plays for.
+type Faction int
+
+// Constants for the factions.
+const (
+	FactionWolf       Faction = iota + 1 // Wolf team.
+	FactionGod                           // Good team, with special abilities.
+	FactionVillager                      // Good team, without abilities.
+	FactionThirdParty                    // Neither team, with their own win condition.
+)
+
+// String returns the string representation of a Faction.
+func (f Faction) String() string {
+	switch f {
+	case FactionWolf:
+		return "狼人陣營"
+	case FactionGod:
+		return "神職"
+	case FactionVillager:
+		return "平民"
+	case FactionThirdParty:
+		return "第三方"
+	default:
+		return "unknown"
+	}
+}
+
+// IsGood reports whether the faction belongs to the good team.
+func (f Faction) IsGood() bool {
+	return f == FactionGod || f == FactionVillager
+}
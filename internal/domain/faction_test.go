@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaction_String(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("狼人陣營", FactionWolf.String())
+	assert.Equal("神職", FactionGod.String())
+	assert.Equal("平民", FactionVillager.String())
+	assert.Equal("第三方", FactionThirdParty.String())
+	assert.Equal("unknown", Faction(99).String())
+}
+
+func TestFaction_IsGood(t *testing.T) {
+	assert := assert.New(t)
+	assert.False(FactionWolf.IsGood())
+	assert.True(FactionGod.IsGood())
+	assert.True(FactionVillager.IsGood())
+	assert.False(FactionThirdParty.IsGood())
+}
+
+func TestIdentity_Faction(t *testing.T) {
+	tests := []struct {
+		identity Identity
+		want     Faction
+	}{
+		{WerewolfKing, FactionWolf},
+		{WhiteWerewolf, FactionWolf},
+		{GhostRider, FactionWolf},
+		{WerewolfBeauty, FactionWolf},
+		{Werewolf, FactionWolf},
+		{Seer, FactionGod},
+		{Witch, FactionGod},
+		{Hunter, FactionGod},
+		{Guard, FactionGod},
+		{Knight, FactionGod},
+		{Magician, FactionGod},
+		{Villager, FactionVillager},
+		{HiddenWolf, FactionWolf},
+		{Cupid, FactionThirdParty},
+		{WildChild, FactionVillager},
+		{Idiot, FactionGod},
+		{BearTamer, FactionGod},
+		{Identity(99), Faction(0)},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.identity.Faction(), "Identity(%d).Faction() mismatch", tt.identity)
+	}
+}
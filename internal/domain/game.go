@@ -0,0 +1,705 @@
+package domain
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Errors returned by Game actions.
+var (
+	ErrGameEnded     = errors.New("game has ended")
+	ErrNotYourTurn   = errors.New("not your turn")
+	ErrInvalidTarget = errors.New("invalid target")
+	ErrPotionUsed    = errors.New("potion already used")
+	ErrShotPending   = errors.New("waiting for the hunter to shoot")
+	ErrAlreadyVoted  = errors.New("already voted")
+)
+
+// Phase represents the stage of a game.
+type Phase int
+
+// Constants for the phases of a game.
+const (
+	PhaseNight Phase = iota + 1 // Night actions are being collected.
+	PhaseDay                    // Night results are announced and players discuss.
+	PhaseVote                   // Players vote on whom to exile.
+	PhaseEnded                  // The game is over.
+)
+
+// String returns the string representation of a Phase.
+func (p Phase) String() string {
+	switch p {
+	case PhaseNight:
+		return "夜晚"
+	case PhaseDay:
+		return "白天"
+	case PhaseVote:
+		return "投票"
+	case PhaseEnded:
+		return "遊戲結束"
+	default:
+		return "unknown"
+	}
+}
+
+// NightStep represents whose turn it is during the night.
+// Steps run in declaration order; steps without a living actor are skipped.
+type NightStep int
+
+// Constants for the night steps.
+const (
+	StepCupid     NightStep = iota + 1 // Cupid links two lovers, on the first night only.
+	StepWildChild                      // Wild Child picks a role model, on the first night only.
+	StepGuard                          // Guard protects a player.
+	StepWolves                         // Wolves choose a kill.
+	StepWitch                          // Witch uses a potion.
+	StepSeer                           // Seer checks a player.
+)
+
+// Action is something a player or the moderator can do in a game.
+type Action int
+
+// Constants for the game actions.
+const (
+	ActionGuard       Action = iota + 1 // Guard protects the target.
+	ActionWolfKill                      // Wolves kill the target.
+	ActionWitchSave                     // Witch saves tonight's wolf target.
+	ActionWitchPoison                   // Witch poisons the target.
+	ActionSeerCheck                     // Seer checks the target.
+	ActionHunterShoot                   // Hunter shoots the target on death.
+	ActionSkip                          // Skip the current action.
+	ActionStartVote                     // Moderator opens the day vote.
+	ActionVote                          // Player votes to exile the target, or abstains if empty.
+	ActionLink                          // Cupid picks the target as one of the lovers.
+	ActionRoleModel                     // Wild Child picks the target as role model.
+)
+
+// Player is a participant together with their state in a game.
+type Player struct {
+	Participant
+	Alive    bool    `json:"alive"`    // Whether the player is still alive.
+	Faction  Faction `json:"faction"`  // Team the player currently plays for; lovers and the Wild Child may switch.
+	Lover    string  `json:"lover"`    // User ID of the player's lover, empty if none.
+	Revealed bool    `json:"revealed"` // Whether the Idiot has been revealed by an exile.
+}
+
+// camp returns the team the player currently plays for, falling back to the identity's faction.
+func (p Player) camp() Faction {
+	if p.Faction != 0 {
+		return p.Faction
+	}
+	return p.Identity.Faction()
+}
+
+// wakesWithWolves reports whether the player opens their eyes with the wolves.
+// Wolves linked to a good lover still do, as does a Wild Child whose role model died.
+func (p Player) wakesWithWolves() bool {
+	return p.Identity.Faction() == FactionWolf || p.camp() == FactionWolf
+}
+
+// looksLikeWolf reports whether the Seer and the bear see the player as a wolf.
+func (p Player) looksLikeWolf() bool {
+	r, _ := LookupRole(p.Identity)
+	return p.wakesWithWolves() && !r.Disguised
+}
+
+// Option is a choice offered to a player in a Prompt.
+type Option struct {
+	Label  string `json:"label"`  // Text shown on the button.
+	Action Action `json:"action"` // Action performed when chosen.
+	Target string `json:"target"` // User ID of the target, empty for none.
+}
+
+// Prompt asks a player to choose one of the options.
+type Prompt struct {
+	UserID  string   `json:"userId"`  // Player who should act.
+	Text    string   `json:"text"`    // Instruction for the player.
+	Options []Option `json:"options"` // Available choices.
+	Turn    int      `json:"turn"`    // Turn the prompt belongs to; its options expire once the turn is over.
+}
+
+// Notice is a message produced by the game.
+type Notice struct {
+	To   string `json:"to"`   // Recipient user ID; empty means a public announcement.
+	Text string `json:"text"` // Message text.
+}
+
+// NightActions records the choices made during the current night.
+type NightActions struct {
+	Guarded    string `json:"guarded"`    // Player protected by the Guard.
+	WolfTarget string `json:"wolfTarget"` // Player chosen by the wolves.
+	Saved      bool   `json:"saved"`      // Whether the Witch saved the wolf target.
+	Poisoned   string `json:"poisoned"`   // Player poisoned by the Witch.
+}
+
+// Game is the state machine driving night, day and vote cycles of a round.
+type Game struct {
+	ModeratorID     string       `json:"moderatorId"`      // User who runs the game.
+	Players         []Player     `json:"players"`          // Players in seating order.
+	Phase           Phase        `json:"phase"`            // Current phase.
+	Step            NightStep    `json:"step"`             // Current night step, valid during PhaseNight.
+	Day             int          `json:"day"`              // Day number, starting at 1 for the first night.
+	Turn            int          `json:"turn"`             // Incremented whenever new prompts are due.
+	Night           NightActions `json:"night"`            // Choices made tonight.
+	LastGuarded     string       `json:"lastGuarded"`      // Player protected the night before.
+	WitchSaveUsed   bool         `json:"witchSaveUsed"`    // Whether the antidote was used.
+	WitchPoisonUsed bool         `json:"witchPoisonUsed"`  // Whether the poison was used.
+	PendingShooter  string       `json:"pendingShooter"`   // Hunter who died and has not shot yet.
+	Rules           Rules        `json:"rules"`            // House rules of the round.
+	Ballot          *Ballot      `json:"ballot,omitempty"` // Open day vote, nil outside PhaseVote.
+	VoteHistory     []VoteRecord `json:"voteHistory"`      // Every finished vote, oldest first.
+	Winner          Winner       `json:"winner"`           // Winning side once the game has ended.
+	Lovers          []string     `json:"lovers"`           // Players linked by Cupid, in the order picked.
+	RoleModel       string       `json:"roleModel"`        // Role model picked by the Wild Child.
+}
+
+// NewGame starts a game with the given participants and rules at the first night.
+func NewGame(moderatorID string, participants []Participant, rules Rules) *Game {
+	g := &Game{
+		ModeratorID: moderatorID,
+		Players:     make([]Player, 0, len(participants)),
+		Rules:       rules,
+	}
+	for _, p := range participants {
+		g.Players = append(g.Players, Player{Participant: p, Alive: true, Faction: p.Identity.Faction()})
+	}
+	// Players sit in seat order, which decides who the bear's neighbours are
+	slices.SortStableFunc(g.Players, func(a, b Player) int { return cmp.Compare(a.Seat, b.Seat) })
+	g.startNight()
+	return g
+}
+
+// Act performs an action by actorID and returns the notices it produced.
+// Night actions advance the night once the current step is done; the moderator drives the day,
+// whose actions the caller must authorize since co-hosts may moderate as well.
+func (g *Game) Act(actorID string, action Action, targetID string) ([]Notice, error) {
+	if g.Phase == PhaseEnded {
+		return nil, ErrGameEnded
+	}
+	if g.PendingShooter != "" {
+		if actorID != g.PendingShooter || (action != ActionHunterShoot && action != ActionSkip) {
+			return nil, ErrShotPending
+		}
+		if action == ActionSkip {
+			targetID = ""
+		}
+		return g.shoot(targetID)
+	}
+
+	switch g.Phase {
+	case PhaseNight:
+		return g.actNight(actorID, action, targetID)
+	case PhaseDay:
+		if action != ActionStartVote {
+			return nil, ErrNotYourTurn
+		}
+		g.openVote(nil)
+		return []Notice{{Text: "開始投票"}}, nil
+	case PhaseVote:
+		if action != ActionVote {
+			return nil, ErrNotYourTurn
+		}
+		return g.castVote(actorID, targetID)
+	}
+	return nil, ErrNotYourTurn
+}
+
+// Prompts returns the prompts due at the current turn, stamped with the turn.
+func (g *Game) Prompts() []Prompt {
+	prompts := g.turnPrompts()
+	for i := range prompts {
+		prompts[i].Turn = g.Turn
+	}
+	return prompts
+}
+
+// turnPrompts builds the prompts due at the current turn.
+func (g *Game) turnPrompts() []Prompt {
+	if g.Phase == PhaseEnded {
+		return nil
+	}
+	if g.PendingShooter != "" {
+		return []Prompt{{
+			UserID:  g.PendingShooter,
+			Text:    "你死亡了，請選擇要開槍帶走的玩家",
+			Options: g.targetOptions(ActionHunterShoot, g.PendingShooter, "不開槍"),
+		}}
+	}
+
+	switch g.Phase {
+	case PhaseNight:
+		return g.nightPrompts()
+	case PhaseDay:
+		return []Prompt{{
+			UserID:  g.ModeratorID,
+			Text:    "討論結束後請開始投票",
+			Options: []Option{{Label: "開始投票", Action: ActionStartVote}},
+		}}
+	case PhaseVote:
+		return g.ballotPrompts()
+	}
+	return nil
+}
+
+// Player returns the player with the given user ID.
+func (g *Game) Player(userID string) (*Player, bool) {
+	for i := range g.Players {
+		if g.Players[i].UserID == userID {
+			return &g.Players[i], true
+		}
+	}
+	return nil, false
+}
+
+// AlivePlayers returns the players who are still alive.
+func (g *Game) AlivePlayers() []Player {
+	var alive []Player
+	for _, p := range g.Players {
+		if p.Alive {
+			alive = append(alive, p)
+		}
+	}
+	return alive
+}
+
+// actNight handles an action of the current night step.
+func (g *Game) actNight(actorID string, action Action, targetID string) ([]Notice, error) {
+	actor, ok := g.Player(actorID)
+	if !ok || !actor.Alive || !g.isStepActor(g.Step, *actor) {
+		return nil, ErrNotYourTurn
+	}
+
+	var notices []Notice
+	switch g.Step {
+	case StepCupid:
+		switch action {
+		case ActionSkip:
+			if len(g.Lovers) > 0 {
+				return nil, ErrInvalidTarget
+			}
+			// Cupid without lovers plays for the good side.
+			actor.Faction = FactionVillager
+		case ActionLink:
+			if !g.isAlive(targetID) || slices.Contains(g.Lovers, targetID) {
+				return nil, ErrInvalidTarget
+			}
+			g.Lovers = append(g.Lovers, targetID)
+			if len(g.Lovers) < 2 {
+				g.Turn++
+				return nil, nil
+			}
+			notices = append(notices, g.link(actorID)...)
+		default:
+			return nil, ErrNotYourTurn
+		}
+	case StepWildChild:
+		switch action {
+		case ActionRoleModel:
+			target, ok := g.Player(targetID)
+			if !ok || !target.Alive || targetID == actorID {
+				return nil, ErrInvalidTarget
+			}
+			g.RoleModel = targetID
+			notices = append(notices, Notice{To: actorID, Text: "你的榜樣是 " + target.Label()})
+		default:
+			return nil, ErrNotYourTurn
+		}
+	case StepGuard:
+		switch action {
+		case ActionSkip:
+			g.Night.Guarded = ""
+		case ActionGuard:
+			if !g.isAlive(targetID) || targetID == g.LastGuarded {
+				return nil, ErrInvalidTarget
+			}
+			g.Night.Guarded = targetID
+		default:
+			return nil, ErrNotYourTurn
+		}
+	case StepWolves:
+		switch action {
+		case ActionSkip:
+			g.Night.WolfTarget = ""
+		case ActionWolfKill:
+			if !g.isAlive(targetID) {
+				return nil, ErrInvalidTarget
+			}
+			g.Night.WolfTarget = targetID
+		default:
+			return nil, ErrNotYourTurn
+		}
+	case StepWitch:
+		switch action {
+		case ActionSkip:
+		case ActionWitchSave:
+			if g.WitchSaveUsed {
+				return nil, ErrPotionUsed
+			}
+			if g.Night.WolfTarget == "" {
+				return nil, ErrInvalidTarget
+			}
+			g.WitchSaveUsed = true
+			g.Night.Saved = true
+		case ActionWitchPoison:
+			if g.WitchPoisonUsed {
+				return nil, ErrPotionUsed
+			}
+			if !g.isAlive(targetID) || targetID == actorID {
+				return nil, ErrInvalidTarget
+			}
+			g.WitchPoisonUsed = true
+			g.Night.Poisoned = targetID
+		default:
+			return nil, ErrNotYourTurn
+		}
+	case StepSeer:
+		switch action {
+		case ActionSkip:
+		case ActionSeerCheck:
+			target, ok := g.Player(targetID)
+			if !ok || !target.Alive || targetID == actorID {
+				return nil, ErrInvalidTarget
+			}
+			result := "好人"
+			if target.looksLikeWolf() {
+				result = "狼人"
+			}
+			notices = append(notices, Notice{To: actorID, Text: target.Label() + " 的身分是 " + result})
+		default:
+			return nil, ErrNotYourTurn
+		}
+	}
+
+	notices = append(notices, g.nextStep()...)
+	return notices, nil
+}
+
+// nightPrompts returns the prompts for the actors of the current night step.
+func (g *Game) nightPrompts() []Prompt {
+	var prompts []Prompt
+	for _, p := range g.Players {
+		if !p.Alive || !g.isStepActor(g.Step, p) {
+			continue
+		}
+		prompt := Prompt{UserID: p.UserID}
+		switch g.Step {
+		case StepCupid:
+			prompt.Text = "丘比特請睜眼，請選擇兩位玩家成為戀人"
+			skip := "不連結"
+			if len(g.Lovers) > 0 {
+				prompt.Text = "請選擇第二位戀人"
+				skip = ""
+			}
+			for _, o := range g.targetOptions(ActionLink, "", skip) {
+				if !slices.Contains(g.Lovers, o.Target) {
+					prompt.Options = append(prompt.Options, o)
+				}
+			}
+		case StepWildChild:
+			prompt.Text = "野孩子請睜眼，請選擇你的榜樣"
+			prompt.Options = g.targetOptions(ActionRoleModel, p.UserID, "")
+		case StepGuard:
+			prompt.Text = "守衛請睜眼，請選擇今晚要守護的玩家"
+			for _, o := range g.targetOptions(ActionGuard, "", "空守") {
+				if o.Target == "" || o.Target != g.LastGuarded {
+					prompt.Options = append(prompt.Options, o)
+				}
+			}
+		case StepWolves:
+			prompt.Text = "狼人請睜眼，請選擇今晚要殺害的玩家"
+			prompt.Options = g.targetOptions(ActionWolfKill, "", "空刀")
+		case StepWitch:
+			prompt.Text = "女巫請睜眼，今晚是平安夜"
+			if target, ok := g.Player(g.Night.WolfTarget); ok {
+				prompt.Text = "女巫請睜眼，今晚 " + target.Label() + " 被殺了"
+				if !g.WitchSaveUsed {
+					prompt.Options = append(prompt.Options, Option{Label: "使用解藥", Action: ActionWitchSave})
+				}
+			}
+			if !g.WitchPoisonUsed {
+				for _, o := range g.targetOptions(ActionWitchPoison, p.UserID, "") {
+					o.Label = "毒 " + o.Label
+					prompt.Options = append(prompt.Options, o)
+				}
+			}
+			prompt.Options = append(prompt.Options, Option{Label: "不使用藥水", Action: ActionSkip})
+		case StepSeer:
+			prompt.Text = "預言家請睜眼，請選擇要查驗的玩家"
+			prompt.Options = g.targetOptions(ActionSeerCheck, p.UserID, "")
+		}
+		prompts = append(prompts, prompt)
+	}
+	return prompts
+}
+
+// targetOptions lists every living player except excludeID as options for action.
+// A non-empty skipLabel appends an option to skip.
+func (g *Game) targetOptions(action Action, excludeID, skipLabel string) []Option {
+	var options []Option
+	for _, p := range g.Players {
+		if p.Alive && p.UserID != excludeID {
+			options = append(options, Option{Label: p.Label(), Action: action, Target: p.UserID})
+		}
+	}
+	if skipLabel != "" {
+		options = append(options, Option{Label: skipLabel, Action: ActionSkip})
+	}
+	return options
+}
+
+// startNight begins a new night at its first step with a living actor.
+// A night without any actor resolves at once, and its notices are returned.
+func (g *Game) startNight() []Notice {
+	g.Phase = PhaseNight
+	g.Day++
+	g.Night = NightActions{}
+	g.Step = 0
+	return g.nextStep()
+}
+
+// nextStep moves to the next night step with a living actor, resolving the night after the last one.
+func (g *Game) nextStep() []Notice {
+	for step := g.Step + 1; step <= StepSeer; step++ {
+		if g.hasStepActor(step) {
+			g.Step = step
+			g.Turn++
+			return nil
+		}
+	}
+	return g.resolveNight()
+}
+
+// resolveNight applies tonight's choices and starts the day.
+func (g *Game) resolveNight() []Notice {
+	var dead []string
+	if t := g.Night.WolfTarget; t != "" {
+		guarded := g.Night.Guarded == t
+		// A player both guarded and saved still dies (同守同救).
+		if guarded == g.Night.Saved {
+			dead = append(dead, t)
+		}
+	}
+	if t := g.Night.Poisoned; t != "" && !slices.Contains(dead, t) {
+		dead = append(dead, t)
+	}
+
+	g.LastGuarded = g.Night.Guarded
+	g.Phase = PhaseDay
+	g.Turn++
+
+	var died []string
+	var private []Notice
+	for _, id := range dead {
+		cause := deathWolves
+		if id == g.Night.Poisoned {
+			cause = deathPoison
+		}
+		ids, ns := g.kill(id, cause)
+		died = append(died, ids...)
+		private = append(private, ns...)
+	}
+
+	notices := []Notice{{Text: "天亮了，昨晚是平安夜"}}
+	if len(died) > 0 {
+		notices = []Notice{{Text: "天亮了，昨晚 " + g.names(died) + " 死亡"}}
+	}
+	notices = append(notices, private...)
+	notices = append(notices, g.bearNotices()...)
+	return append(notices, g.endIfWon()...)
+}
+
+// exile removes the target from the game, or nobody if targetID is empty, and starts the next night.
+func (g *Game) exile(targetID string) []Notice {
+	g.Ballot = nil
+	if targetID == "" {
+		return append([]Notice{{Text: "本輪無人被放逐，天黑請閉眼"}}, g.startNight()...)
+	}
+
+	target, _ := g.Player(targetID)
+	// The Idiot survives the first exile by revealing, but loses the right to vote.
+	if target.Identity == Idiot && !target.Revealed {
+		target.Revealed = true
+		notices := []Notice{{Text: target.Label() + " 翻牌，身分是白痴，免於放逐"}, {Text: "天黑請閉眼"}}
+		return append(notices, g.startNight()...)
+	}
+
+	notices := []Notice{{Text: target.Label() + " 被放逐"}}
+	notices = append(notices, g.deathNotices(g.kill(targetID, deathExile))...)
+	if g.PendingShooter != "" {
+		g.Turn++
+		return notices
+	}
+	if end := g.endIfWon(); end != nil {
+		return append(notices, end...)
+	}
+	notices = append(notices, Notice{Text: "天黑請閉眼"})
+	return append(notices, g.startNight()...)
+}
+
+// shoot resolves the pending Hunter shot at targetID, or no shot if the action was skipped.
+func (g *Game) shoot(targetID string) ([]Notice, error) {
+	hunter, _ := g.Player(g.PendingShooter)
+	if targetID != "" && !g.isAlive(targetID) {
+		return nil, ErrInvalidTarget
+	}
+	g.PendingShooter = ""
+
+	var notices []Notice
+	if targetID != "" {
+		target, _ := g.Player(targetID)
+		notices = append(notices, Notice{Text: "獵人 " + hunter.Label() + " 開槍帶走了 " + target.Label()})
+		notices = append(notices, g.deathNotices(g.kill(targetID, deathShot))...)
+	} else {
+		notices = append(notices, Notice{Text: "獵人 " + hunter.Label() + " 沒有開槍"})
+	}
+	if end := g.endIfWon(); end != nil {
+		return append(notices, end...), nil
+	}
+
+	// The shot after an exile ends the day.
+	if g.Phase == PhaseVote {
+		notices = append(notices, Notice{Text: "天黑請閉眼"})
+		return append(notices, g.startNight()...), nil
+	}
+	g.Turn++
+	return notices, nil
+}
+
+// endIfWon ends the game if a side has won and returns the announcement, or nil if the game goes on.
+// The check waits while a Hunter still has to shoot, since the shot may change the outcome.
+func (g *Game) endIfWon() []Notice {
+	if g.PendingShooter != "" {
+		return nil
+	}
+	winner := CheckWinner(g.Players, g.Rules.WinRule)
+	if winner == WinnerNone {
+		return nil
+	}
+
+	g.Phase = PhaseEnded
+	g.Winner = winner
+	g.Ballot = nil
+	g.Turn++
+
+	var sb strings.Builder
+	sb.WriteString("遊戲結束，")
+	sb.WriteString(winner.String())
+	sb.WriteString("獲勝")
+	for _, p := range g.Players {
+		sb.WriteString("\n")
+		sb.WriteString(p.Label())
+		sb.WriteString(": ")
+		sb.WriteString(p.Identity.String())
+		if p.Lover != "" {
+			sb.WriteString(" (戀人)")
+		}
+		if !p.Alive {
+			sb.WriteString(" (死亡)")
+		}
+	}
+	return []Notice{{Text: sb.String()}}
+}
+
+// isStepActor reports whether a player acts during the given night step.
+func (g *Game) isStepActor(step NightStep, p Player) bool {
+	switch step {
+	case StepCupid:
+		return p.Identity == Cupid && g.Day == 1
+	case StepWildChild:
+		return p.Identity == WildChild && g.Day == 1
+	case StepGuard:
+		return p.Identity == Guard
+	case StepWolves:
+		// The Hidden Wolf only joins the kill once it is the last wolf standing.
+		if p.Identity == HiddenWolf {
+			return p.wakesWithWolves() && !g.hasKillingWolf()
+		}
+		return p.wakesWithWolves()
+	case StepWitch:
+		return p.Identity == Witch && (!g.WitchSaveUsed || !g.WitchPoisonUsed)
+	case StepSeer:
+		return p.Identity == Seer
+	}
+	return false
+}
+
+// hasKillingWolf reports whether a living wolf other than the Hidden Wolf wakes at night.
+func (g *Game) hasKillingWolf() bool {
+	for _, p := range g.Players {
+		if p.Alive && p.Identity != HiddenWolf && p.wakesWithWolves() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasStepActor reports whether a living player acts during the given night step.
+func (g *Game) hasStepActor(step NightStep) bool {
+	for _, p := range g.Players {
+		if p.Alive && g.isStepActor(step, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlive reports whether userID is a living player.
+func (g *Game) isAlive(userID string) bool {
+	p, ok := g.Player(userID)
+	return ok && p.Alive
+}
+
+// link pairs the two lovers picked by Cupid and tells them about each other.
+// Lovers from opposite sides form a third party together with Cupid; otherwise Cupid plays for the good side.
+func (g *Game) link(cupidID string) []Notice {
+	a, _ := g.Player(g.Lovers[0])
+	b, _ := g.Player(g.Lovers[1])
+	a.Lover, b.Lover = b.UserID, a.UserID
+
+	cupid, _ := g.Player(cupidID)
+	cupid.Faction = FactionVillager
+	if (a.camp() == FactionWolf) != (b.camp() == FactionWolf) {
+		cupid.Faction = FactionThirdParty
+		a.Faction = FactionThirdParty
+		b.Faction = FactionThirdParty
+	}
+
+	notices := []Notice{{To: cupidID, Text: "你讓 " + a.Label() + "、" + b.Label() + " 成為了戀人"}}
+	for _, p := range []*Player{a, b} {
+		lover, _ := g.Player(p.Lover)
+		text := "你與 " + lover.Label() + " 成為了戀人，對方的身分是 " + lover.Identity.String()
+		if p.Faction == FactionThirdParty {
+			text += "\n你們與丘比特組成第三方陣營，需淘汰其他所有玩家才能獲勝"
+		}
+		notices = append(notices, Notice{To: p.UserID, Text: text})
+	}
+	return notices
+}
+
+// bearNotices announces whether the bear growls at dawn: it does when a living neighbour of the
+// Bear Tamer, in seating order, looks like a wolf. Nothing is announced once the Bear Tamer is dead.
+func (g *Game) bearNotices() []Notice {
+	for i, p := range g.Players {
+		if p.Identity != BearTamer || !p.Alive {
+			continue
+		}
+		if g.neighbourLooksLikeWolf(i, 1) || g.neighbourLooksLikeWolf(i, -1) {
+			return []Notice{{Text: "熊咆哮了"}}
+		}
+		return []Notice{{Text: "熊沒有咆哮"}}
+	}
+	return nil
+}
+
+// neighbourLooksLikeWolf reports whether the nearest living player from seat i in direction dir looks like a wolf.
+func (g *Game) neighbourLooksLikeWolf(i, dir int) bool {
+	n := len(g.Players)
+	for j := (i + dir + n) % n; j != i; j = (j + dir + n) % n {
+		if g.Players[j].Alive {
+			return g.Players[j].looksLikeWolf()
+		}
+	}
+	return false
+}
@@ -0,0 +1,503 @@
+package domain
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSeededGame deals a standard board with a seeded shuffler and starts its game.
+func newSeededGame(t *testing.T, seed uint64) (*Round, *Game) {
+	t.Helper()
+	orig := Rng
+	Rng = NewSeededShuffler(seed)
+	t.Cleanup(func() { Rng = orig })
+
+	r := NewRound("owner", "000001")
+	r.SetIdentity("owner", Werewolf, 2)
+	r.SetIdentity("owner", Seer, 1)
+	r.SetIdentity("owner", Witch, 1)
+	r.SetIdentity("owner", Hunter, 1)
+	r.SetIdentity("owner", Guard, 1)
+	r.SetIdentity("owner", Villager, 2)
+	for i := range len(r.Identities) {
+		r.Register("user"+strconv.Itoa(i), "P"+strconv.Itoa(i), "")
+	}
+	require.NoError(t, r.StartGame("owner"))
+	return r, r.Game
+}
+
+// playersWith returns the user IDs of the players with the given identity.
+func playersWith(g *Game, iden Identity) []string {
+	var ids []string
+	for _, p := range g.Players {
+		if p.Identity == iden {
+			ids = append(ids, p.UserID)
+		}
+	}
+	return ids
+}
+
+// firstVillager returns a user ID of a Villager.
+func firstVillager(g *Game) string {
+	return playersWith(g, Villager)[0]
+}
+
+func TestNewSeededShuffler_Deterministic(t *testing.T) {
+	r1, _ := newSeededGame(t, 42)
+	r2, _ := newSeededGame(t, 42)
+	r3, _ := newSeededGame(t, 7)
+
+	assert.Equal(t, r1.Identities, r2.Identities, "Same seed should deal the same identities")
+	assert.NotEqual(t, r1.Identities, r3.Identities, "Different seeds should deal differently")
+}
+
+func TestRound_StartGame(t *testing.T) {
+	r := NewRound("owner", "000001")
+	r.SetIdentity("owner", Werewolf, 1)
+	r.SetIdentity("owner", Villager, 1)
+	r.Register("user1", "User One", "")
+	assert := assert.New(t)
+
+	assert.ErrorIs(r.StartGame("owner"), ErrRegistrationOpen)
+	r.Register("user2", "User Two", "")
+	assert.ErrorIs(r.StartGame("user1"), ErrNotPermitted)
+	assert.NoError(r.StartGame("owner"))
+	assert.Equal(PhaseNight, r.Game.Phase)
+
+	r.Again()
+	assert.Nil(r.Game, "Again should drop the finished game")
+}
+
+func TestGame_FullCycle(t *testing.T) {
+	_, g := newSeededGame(t, 1)
+	assert := assert.New(t)
+	guard := playersWith(g, Guard)[0]
+	wolves := playersWith(g, Werewolf)
+	witch := playersWith(g, Witch)[0]
+	seer := playersWith(g, Seer)[0]
+	victim := firstVillager(g)
+
+	// Night 1: guard -> wolves -> witch -> seer.
+	assert.Equal(PhaseNight, g.Phase)
+	assert.Equal(1, g.Day)
+	assert.Equal(StepGuard, g.Step)
+	prompts := g.Prompts()
+	require.Len(t, prompts, 1)
+	assert.Equal(guard, prompts[0].UserID)
+
+	_, err := g.Act(wolves[0], ActionWolfKill, victim)
+	assert.ErrorIs(err, ErrNotYourTurn, "Wolves must wait for the guard")
+
+	_, err = g.Act(guard, ActionGuard, guard)
+	require.NoError(t, err)
+	assert.Equal(StepWolves, g.Step)
+	assert.Len(g.Prompts(), 2, "Every wolf should be prompted")
+
+	_, err = g.Act(wolves[1], ActionWolfKill, victim)
+	require.NoError(t, err)
+	assert.Equal(StepWitch, g.Step)
+	assert.Contains(g.Prompts()[0].Text, g.Players[indexOf(g, victim)].Label())
+
+	_, err = g.Act(witch, ActionSkip, "")
+	require.NoError(t, err)
+	assert.Equal(StepSeer, g.Step)
+
+	notices, err := g.Act(seer, ActionSeerCheck, wolves[0])
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(notices), 2)
+	assert.Equal(Notice{To: seer, Text: g.Players[indexOf(g, wolves[0])].Label() + " 的身分是 狼人"}, notices[0])
+	assert.Contains(notices[1].Text, "死亡")
+
+	// Day 1.
+	assert.Equal(PhaseDay, g.Phase)
+	p, _ := g.Player(victim)
+	assert.False(p.Alive, "Wolf target should die")
+	_, err = g.Act(wolves[0], ActionVote, victim)
+	assert.ErrorIs(err, ErrNotYourTurn, "Voting waits for the moderator")
+	_, err = g.Act("owner", ActionStartVote, "")
+	require.NoError(t, err)
+	assert.Equal(PhaseVote, g.Phase)
+
+	_, err = g.Act(wolves[0], ActionVote, victim)
+	assert.ErrorIs(err, ErrInvalidTarget, "Dead players cannot be voted for")
+	voteOut(t, g, wolves[0])
+
+	// Night 2.
+	assert.Equal(PhaseNight, g.Phase)
+	assert.Equal(2, g.Day)
+	assert.Len(g.AlivePlayers(), 6)
+}
+
+func TestGame_GuardCannotRepeat(t *testing.T) {
+	_, g := newSeededGame(t, 2)
+	guard := playersWith(g, Guard)[0]
+	villager := firstVillager(g)
+
+	_, err := g.Act(guard, ActionGuard, villager)
+	require.NoError(t, err)
+	skipNight(t, g)
+	advanceToNight(t, g)
+
+	for _, o := range g.Prompts()[0].Options {
+		assert.NotEqual(t, villager, o.Target, "Last night's target should not be offered")
+	}
+	_, err = g.Act(guard, ActionGuard, villager)
+	assert.ErrorIs(t, err, ErrInvalidTarget)
+}
+
+func TestGame_GuardBlocksKill(t *testing.T) {
+	_, g := newSeededGame(t, 3)
+	villager := firstVillager(g)
+
+	act(t, g, playersWith(g, Guard)[0], ActionGuard, villager)
+	act(t, g, playersWith(g, Werewolf)[0], ActionWolfKill, villager)
+	act(t, g, playersWith(g, Witch)[0], ActionSkip, "")
+	notices := act(t, g, playersWith(g, Seer)[0], ActionSkip, "")
+
+	assert.Equal(t, "天亮了，昨晚是平安夜", notices[len(notices)-1].Text)
+	assert.True(t, g.isAlive(villager))
+}
+
+func TestGame_GuardAndSaveKills(t *testing.T) {
+	_, g := newSeededGame(t, 4)
+	villager := firstVillager(g)
+
+	act(t, g, playersWith(g, Guard)[0], ActionGuard, villager)
+	act(t, g, playersWith(g, Werewolf)[0], ActionWolfKill, villager)
+	act(t, g, playersWith(g, Witch)[0], ActionWitchSave, "")
+	act(t, g, playersWith(g, Seer)[0], ActionSkip, "")
+
+	assert.False(t, g.isAlive(villager), "Guarded and saved player dies")
+}
+
+func TestGame_WitchPotions(t *testing.T) {
+	_, g := newSeededGame(t, 5)
+	witch := playersWith(g, Witch)[0]
+	villagers := playersWith(g, Villager)
+	assert := assert.New(t)
+
+	// Night 1: save the victim.
+	act(t, g, playersWith(g, Guard)[0], ActionSkip, "")
+	act(t, g, playersWith(g, Werewolf)[0], ActionWolfKill, villagers[0])
+	act(t, g, witch, ActionWitchSave, "")
+	act(t, g, playersWith(g, Seer)[0], ActionSkip, "")
+	assert.True(g.isAlive(villagers[0]), "Saved player survives")
+	assert.True(g.WitchSaveUsed)
+	advanceToNight(t, g)
+
+	// Night 2: the antidote is gone, poison someone.
+	act(t, g, playersWith(g, Guard)[0], ActionSkip, "")
+	act(t, g, playersWith(g, Werewolf)[0], ActionWolfKill, villagers[0])
+	for _, o := range g.Prompts()[0].Options {
+		assert.NotEqual(ActionWitchSave, o.Action, "Used antidote should not be offered")
+	}
+	_, err := g.Act(witch, ActionWitchSave, "")
+	assert.ErrorIs(err, ErrPotionUsed)
+	_, err = g.Act(witch, ActionWitchPoison, witch)
+	assert.ErrorIs(err, ErrInvalidTarget, "Witch cannot poison herself")
+	hunter := playersWith(g, Hunter)[0]
+	act(t, g, witch, ActionWitchPoison, hunter)
+	notices := act(t, g, playersWith(g, Seer)[0], ActionSkip, "")
+
+	assert.False(g.isAlive(villagers[0]))
+	assert.False(g.isAlive(hunter))
+	assert.Contains(notices[len(notices)-1].Text, "、", "Both deaths should be announced")
+	advanceToNight(t, g)
+
+	// Night 3: the Witch has no potions left and is skipped.
+	act(t, g, playersWith(g, Guard)[0], ActionSkip, "")
+	act(t, g, playersWith(g, Werewolf)[0], ActionSkip, "")
+	assert.Equal(StepSeer, g.Step)
+}
+
+func TestGame_HunterShootsOnDeath(t *testing.T) {
+	_, g := newSeededGame(t, 6)
+	hunter := playersWith(g, Hunter)[0]
+	wolf := playersWith(g, Werewolf)[0]
+	assert := assert.New(t)
+
+	act(t, g, playersWith(g, Guard)[0], ActionSkip, "")
+	act(t, g, wolf, ActionWolfKill, hunter)
+	act(t, g, playersWith(g, Witch)[0], ActionSkip, "")
+	act(t, g, playersWith(g, Seer)[0], ActionSkip, "")
+
+	assert.Equal(hunter, g.PendingShooter)
+	prompts := g.Prompts()
+	require.Len(t, prompts, 1)
+	assert.Equal(hunter, prompts[0].UserID)
+	_, err := g.Act("owner", ActionStartVote, "")
+	assert.ErrorIs(err, ErrShotPending, "Day waits for the hunter")
+
+	notices := act(t, g, hunter, ActionHunterShoot, wolf)
+	assert.Contains(notices[0].Text, "開槍帶走了")
+	assert.False(g.isAlive(wolf))
+	assert.Equal(PhaseDay, g.Phase)
+	assert.Empty(g.PendingShooter)
+}
+
+func TestGame_HunterSkipIgnoresTarget(t *testing.T) {
+	_, g := newSeededGame(t, 6)
+	hunter := playersWith(g, Hunter)[0]
+	wolf := playersWith(g, Werewolf)[0]
+
+	act(t, g, playersWith(g, Guard)[0], ActionSkip, "")
+	act(t, g, wolf, ActionWolfKill, hunter)
+	act(t, g, playersWith(g, Witch)[0], ActionSkip, "")
+	act(t, g, playersWith(g, Seer)[0], ActionSkip, "")
+	require.Equal(t, hunter, g.PendingShooter)
+
+	notices := act(t, g, hunter, ActionSkip, wolf)
+	assert.Contains(t, notices[0].Text, "沒有開槍")
+	assert.True(t, g.isAlive(wolf), "A skip should not shoot the target it carries")
+	assert.Empty(t, g.PendingShooter)
+}
+
+func TestGame_PoisonedHunterCannotShoot(t *testing.T) {
+	_, g := newSeededGame(t, 7)
+	hunter := playersWith(g, Hunter)[0]
+
+	act(t, g, playersWith(g, Guard)[0], ActionSkip, "")
+	act(t, g, playersWith(g, Werewolf)[0], ActionSkip, "")
+	act(t, g, playersWith(g, Witch)[0], ActionWitchPoison, hunter)
+	act(t, g, playersWith(g, Seer)[0], ActionSkip, "")
+
+	assert.False(t, g.isAlive(hunter))
+	assert.Empty(t, g.PendingShooter)
+}
+
+func TestGame_ExiledHunterShootsBeforeNight(t *testing.T) {
+	_, g := newSeededGame(t, 8)
+	hunter := playersWith(g, Hunter)[0]
+	wolf := playersWith(g, Werewolf)[0]
+
+	skipNight(t, g)
+	act(t, g, "owner", ActionStartVote, "")
+	voteOut(t, g, hunter)
+	assert.Equal(t, PhaseVote, g.Phase)
+	assert.Equal(t, hunter, g.PendingShooter)
+
+	act(t, g, hunter, ActionHunterShoot, wolf)
+	assert.Equal(t, PhaseNight, g.Phase)
+	assert.Equal(t, 2, g.Day)
+}
+
+// act performs an action that must succeed.
+func act(t *testing.T, g *Game, actorID string, action Action, targetID string) []Notice {
+	t.Helper()
+	notices, err := g.Act(actorID, action, targetID)
+	require.NoError(t, err)
+	return notices
+}
+
+// skipNight lets every remaining actor of the current night skip.
+func skipNight(t *testing.T, g *Game) {
+	t.Helper()
+	for g.Phase == PhaseNight {
+		act(t, g, g.Prompts()[0].UserID, ActionSkip, "")
+	}
+}
+
+// advanceToNight runs the day with every player abstaining.
+func advanceToNight(t *testing.T, g *Game) {
+	t.Helper()
+	act(t, g, g.ModeratorID, ActionStartVote, "")
+	for _, p := range g.Voters() {
+		act(t, g, p.UserID, ActionVote, "")
+	}
+}
+
+// voteOut makes every voter vote for the target, which abstains itself.
+func voteOut(t *testing.T, g *Game, targetID string) {
+	t.Helper()
+	for _, p := range g.Voters() {
+		if p.UserID == targetID {
+			act(t, g, p.UserID, ActionVote, "")
+			continue
+		}
+		act(t, g, p.UserID, ActionVote, targetID)
+	}
+}
+
+// indexOf returns the index of the player with the given user ID.
+func indexOf(g *Game, userID string) int {
+	for i, p := range g.Players {
+		if p.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+// newBoardGame starts a game with one player per identity, seated in the given order.
+// Players have user IDs "p0", "p1", ... and names "P0", "P1", ...
+func newBoardGame(t *testing.T, identities ...Identity) *Game {
+	t.Helper()
+	participants := make([]Participant, 0, len(identities))
+	for i, iden := range identities {
+		participants = append(participants, Participant{UserID: "p" + strconv.Itoa(i), Name: "P" + strconv.Itoa(i), Identity: iden})
+	}
+	return NewGame("owner", participants, DefaultRules())
+}
+
+func TestGame_CupidLinksLovers(t *testing.T) {
+	g := newBoardGame(t, Cupid, Werewolf, Villager, Seer, Villager, Werewolf)
+	assert := assert.New(t)
+
+	require.Equal(t, StepCupid, g.Step)
+	assert.Nil(act(t, g, "p0", ActionLink, "p1"), "The first lover is kept until the second is picked")
+	assert.Equal(StepCupid, g.Step)
+	prompts := g.Prompts()
+	require.Len(t, prompts, 1)
+	assert.Equal("請選擇第二位戀人", prompts[0].Text)
+	for _, o := range prompts[0].Options {
+		assert.NotEqual("p1", o.Target)
+		assert.NotEqual(ActionSkip, o.Action, "Cupid cannot stop after the first lover")
+	}
+	_, err := g.Act("p0", ActionLink, "p1")
+	assert.ErrorIs(err, ErrInvalidTarget)
+	_, err = g.Act("p0", ActionSkip, "")
+	assert.ErrorIs(err, ErrInvalidTarget)
+
+	notices := act(t, g, "p0", ActionLink, "p2")
+	require.Len(t, notices, 3)
+	assert.Equal(Notice{To: "p0", Text: "你讓 P1、P2 成為了戀人"}, notices[0])
+	assert.Equal("p1", notices[1].To)
+	assert.Contains(notices[1].Text, "你與 P2 成為了戀人，對方的身分是 平民")
+	assert.Equal("p2", notices[2].To)
+	assert.Equal(StepWolves, g.Step)
+
+	// A wolf and a villager make a third party with Cupid.
+	for _, id := range []string{"p0", "p1", "p2"} {
+		p, _ := g.Player(id)
+		assert.Equal(FactionThirdParty, p.Faction, id)
+	}
+	assert.Equal("p2", g.Players[1].Lover)
+	assert.Equal("p1", g.Players[2].Lover)
+
+	// The linked wolf still wakes with the wolves; killing its lover breaks its heart.
+	assert.Len(g.Prompts(), 2)
+	act(t, g, "p5", ActionWolfKill, "p2")
+	notices = act(t, g, "p3", ActionSkip, "")
+	assert.Equal("天亮了，昨晚 P2、P1 死亡", notices[0].Text)
+	assert.False(g.isAlive("p1"))
+	assert.Equal(PhaseDay, g.Phase, "Cupid keeps the third party alive")
+}
+
+func TestGame_CupidSameSideLovers(t *testing.T) {
+	g := newBoardGame(t, Cupid, Werewolf, Villager, Seer, Villager)
+
+	act(t, g, "p0", ActionLink, "p2")
+	act(t, g, "p0", ActionLink, "p4")
+
+	assert.Equal(t, FactionVillager, g.Players[0].Faction, "Cupid joins the lovers' side")
+	assert.Equal(t, FactionVillager, g.Players[2].Faction)
+	assert.Equal(t, FactionVillager, g.Players[4].Faction)
+}
+
+func TestGame_CupidSkips(t *testing.T) {
+	g := newBoardGame(t, Cupid, Werewolf, Villager)
+
+	act(t, g, "p0", ActionSkip, "")
+
+	assert.Equal(t, FactionVillager, g.Players[0].Faction)
+	assert.Empty(t, g.Lovers)
+	assert.Equal(t, StepWolves, g.Step)
+}
+
+func TestGame_CupidOnlyActsOnFirstNight(t *testing.T) {
+	g := newBoardGame(t, Cupid, Werewolf, Villager, Villager, Villager)
+
+	act(t, g, "p0", ActionSkip, "")
+	skipNight(t, g)
+	advanceToNight(t, g)
+
+	assert.Equal(t, 2, g.Day)
+	assert.Equal(t, StepWolves, g.Step)
+}
+
+func TestGame_WildChildTurnsWolf(t *testing.T) {
+	g := newBoardGame(t, WildChild, Werewolf, Villager, Villager, Seer)
+	assert := assert.New(t)
+
+	require.Equal(t, StepWildChild, g.Step)
+	_, err := g.Act("p0", ActionRoleModel, "p0")
+	assert.ErrorIs(err, ErrInvalidTarget)
+	notices := act(t, g, "p0", ActionRoleModel, "p2")
+	assert.Equal(Notice{To: "p0", Text: "你的榜樣是 P2"}, notices[0])
+
+	act(t, g, "p1", ActionWolfKill, "p2")
+	notices = act(t, g, "p4", ActionSeerCheck, "p0")
+	assert.Equal(Notice{To: "p4", Text: "P0 的身分是 好人"}, notices[0])
+	assert.Contains(notices, Notice{To: "p0", Text: "你的榜樣 P2 已死亡，你成為狼人，今晚起與狼人一起行動"})
+	assert.Equal(FactionWolf, g.Players[0].Faction)
+
+	advanceToNight(t, g)
+	require.Equal(t, StepWolves, g.Step)
+	assert.Len(g.Prompts(), 2, "The Wild Child wakes with the wolves")
+	act(t, g, "p0", ActionSkip, "")
+	notices = act(t, g, "p4", ActionSeerCheck, "p0")
+	assert.Equal(Notice{To: "p4", Text: "P0 的身分是 狼人"}, notices[0])
+}
+
+func TestGame_IdiotSurvivesFirstExile(t *testing.T) {
+	g := newBoardGame(t, Werewolf, Idiot, Villager, Villager, Villager)
+	assert := assert.New(t)
+
+	skipNight(t, g)
+	act(t, g, "owner", ActionStartVote, "")
+	voteOut(t, g, "p1")
+
+	assert.True(g.isAlive("p1"))
+	assert.True(g.Players[1].Revealed)
+	assert.Equal(PhaseNight, g.Phase)
+
+	skipNight(t, g)
+	act(t, g, "owner", ActionStartVote, "")
+	assert.Len(g.Voters(), 4, "A revealed Idiot cannot vote")
+	voteOut(t, g, "p1")
+	assert.False(g.isAlive("p1"), "The second exile kills the Idiot")
+}
+
+func TestGame_BearGrowls(t *testing.T) {
+	g := newBoardGame(t, BearTamer, Villager, Werewolf, Villager, Villager)
+
+	skipNight(t, g)
+	require.Equal(t, PhaseDay, g.Phase)
+	advanceToNight(t, g)
+
+	// With P1 dead, the Bear Tamer sits next to the wolf.
+	notices := act(t, g, "p2", ActionWolfKill, "p1")
+	assert.Equal(t, []Notice{{Text: "天亮了，昨晚 P1 死亡"}, {Text: "熊咆哮了"}}, notices)
+}
+
+func TestGame_BearQuietAtFirstDawn(t *testing.T) {
+	g := newBoardGame(t, BearTamer, Villager, Werewolf, Villager, Villager)
+
+	notices := act(t, g, "p2", ActionSkip, "")
+
+	assert.Equal(t, []Notice{{Text: "天亮了，昨晚是平安夜"}, {Text: "熊沒有咆哮"}}, notices)
+}
+
+func TestGame_HiddenWolf(t *testing.T) {
+	g := newBoardGame(t, Werewolf, HiddenWolf, BearTamer, Seer, Villager, Villager)
+	assert := assert.New(t)
+
+	prompts := g.Prompts()
+	require.Len(t, prompts, 1, "The Hidden Wolf does not wake while another wolf lives")
+	assert.Equal("p0", prompts[0].UserID)
+
+	act(t, g, "p0", ActionSkip, "")
+	notices := act(t, g, "p3", ActionSeerCheck, "p1")
+	assert.Equal(Notice{To: "p3", Text: "P1 的身分是 好人"}, notices[0])
+	assert.Contains(notices, Notice{Text: "熊沒有咆哮"}, "The bear does not smell the Hidden Wolf")
+
+	act(t, g, "owner", ActionStartVote, "")
+	voteOut(t, g, "p0")
+	require.Equal(t, PhaseNight, g.Phase)
+	prompts = g.Prompts()
+	require.Len(t, prompts, 1)
+	assert.Equal("p1", prompts[0].UserID, "The last wolf standing joins the kill")
+}
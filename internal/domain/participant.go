@@ -1,62 +1,61 @@
 package domain
 
+import "strconv"
+
 // Identity represents the role of a player in the game.
 type Identity int
 
 // Constants for different identities (roles) in the game.
+// See the role registry in role.go for their names and factions.
 const (
-	WerewolfKing   Identity = iota + 1 // Werewolf King, Wolf team
-	WhiteWerewolf                      // White Werewolf, Wolf team
-	GhostRider                         // Ghost Rider, Wolf team
-	WerewolfBeauty                     // Werewolf Beauty, Wolf team
-	Werewolf                           // Werewolf, Wolf team
-	Seer                               // Seer, Villager team
-	Witch                              // Witch, Villager team
-	Hunter                             // Hunter, Villager team
-	Guard                              // Guard, Villager team
-	Knight                             // Knight, Villager team
-	Magician                           // Magician, Villager team
-	Villager                           // Villager, Villager team
+	WerewolfKing   Identity = iota + 1 // Werewolf King
+	WhiteWerewolf                      // White Werewolf
+	GhostRider                         // Ghost Rider
+	WerewolfBeauty                     // Werewolf Beauty
+	Werewolf                           // Werewolf
+	Seer                               // Seer
+	Witch                              // Witch
+	Hunter                             // Hunter
+	Guard                              // Guard
+	Knight                             // Knight
+	Magician                           // Magician
+	Villager                           // Villager
+	HiddenWolf                         // Hidden Wolf
+	Cupid                              // Cupid
+	WildChild                          // Wild Child
+	Idiot                              // Idiot
+	BearTamer                          // Bear Tamer
 )
 
 // String returns the string representation of an Identity.
 func (iden Identity) String() string {
-	switch iden {
-	case WerewolfKing:
-		return "狼王"
-	case WhiteWerewolf:
-		return "白狼王"
-	case GhostRider:
-		return "惡靈騎士"
-	case WerewolfBeauty:
-		return "狼美人"
-	case Werewolf:
-		return "狼人"
-	case Seer:
-		return "預言家"
-	case Witch:
-		return "女巫"
-	case Hunter:
-		return "獵人"
-	case Guard:
-		return "守衛"
-	case Knight:
-		return "騎士"
-	case Magician:
-		return "魔術師"
-	case Villager:
-		return "平民"
-	default: // Default for unhandled identities.
-		return "unknown"
+	if r, ok := LookupRole(iden); ok {
+		return r.Name
 	}
+	return "unknown" // Default for unhandled identities.
+}
+
+// Faction returns the faction an Identity plays for, or 0 for unknown identities.
+func (iden Identity) Faction() Faction {
+	r, _ := LookupRole(iden)
+	return r.Faction
 }
 
 // Participant represents a player in the game.
 type Participant struct {
-	UserID     string   // User ID of the participant.
-	Name       string   // Name of the participant.
-	PictureURL string   // URL of the participant's picture.
-	Identity   Identity // Assigned identity (role) of the participant.
+	UserID     string   `json:"userId"`     // User ID of the participant.
+	Name       string   `json:"name"`       // Name of the participant.
+	PictureURL string   `json:"pictureUrl"` // URL of the participant's picture.
+	Identity   Identity `json:"identity"`   // Assigned identity (role) of the participant.
+	Seat       int      `json:"seat"`       // Seat number at the table, from 1; 0 in rounds stored before seats existed.
+}
+
+// Label returns how the participant is announced at the table, e.g. "3號 小明", or the bare name without a seat.
+func (p Participant) Label() string {
+	if p.Seat == 0 {
+		return p.Name
+	}
+	return strconv.Itoa(p.Seat) + "號 " + p.Name
 }
 
 // NewParticipant creates a new participant.
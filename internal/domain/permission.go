@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Errors returned by host and co-host operations.
+var (
+	ErrNotPermitted = errors.New("not permitted in the round")
+	ErrNotCoHost    = errors.New("not a co-host of the round")
+	ErrRoundFull    = errors.New("every identity is taken")
+)
+
+// Permission is a privileged action on a round.
+type Permission int
+
+// Constants for the permissions on a round.
+const (
+	PermOpenRoom  Permission = iota + 1 // Open the room dashboard.
+	PermViewRoles                       // See every participant's identity.
+	PermReshuffle                       // Deal the identities again.
+	PermModerate                        // Start and run the game engine.
+	PermManage                          // Kick, replace and seat participants, close the round.
+	PermGrant                           // Grant and revoke co-hosts, choose whether the owner plays.
+)
+
+// String returns the string representation of a Permission.
+func (p Permission) String() string {
+	switch p {
+	case PermOpenRoom:
+		return "open room"
+	case PermViewRoles:
+		return "view roles"
+	case PermReshuffle:
+		return "reshuffle"
+	case PermModerate:
+		return "moderate"
+	case PermManage:
+		return "manage"
+	case PermGrant:
+		return "grant"
+	}
+	return "unknown"
+}
+
+// CoHost is a user the owner lets help moderate the round.
+type CoHost struct {
+	UserID    string    `json:"userId"`    // User ID of the co-host.
+	Name      string    `json:"name"`      // Display name of the co-host.
+	GrantedAt time.Time `json:"grantedAt"` // Time the owner first granted the rights; zero in rounds stored before it existed.
+}
+
+// Can reports whether the user holds the permission on the round.
+// The owner holds every permission, except seeing the roles and moderating while playing.
+// Co-hosts may open the room, see the roles, reshuffle and moderate; those who also play may not see the roles or moderate.
+// Hosts who play see the roles once the game has ended.
+func (r *Round) Can(userID string, perm Permission) bool {
+	var plays bool
+	switch {
+	case r.IsOwner(userID):
+		plays = r.OwnerPlays
+	case r.IsCoHost(userID):
+		if perm == PermManage || perm == PermGrant {
+			return false
+		}
+		plays = r.participantIndex(userID) >= 0
+	default:
+		return false
+	}
+	switch perm {
+	case PermViewRoles:
+		return !plays || r.GameEnded()
+	case PermModerate:
+		return !plays
+	}
+	return true
+}
+
+// IsMember reports whether the user owns, co-hosts or plays in the round.
+func (r *Round) IsMember(userID string) bool {
+	return r.IsOwner(userID) || r.IsCoHost(userID) || r.participantIndex(userID) >= 0
+}
+
+// IsCoHost reports whether the user is a co-host of the round.
+func (r *Round) IsCoHost(userID string) bool {
+	return r.coHostIndex(userID) >= 0
+}
+
+// GrantCoHost makes the user a co-host of the round. Only the owner can grant, and not to a player.
+func (r *Round) GrantCoHost(ownerID, userID, name string) error {
+	if !r.Can(ownerID, PermGrant) {
+		return ErrNotPermitted
+	}
+	if r.IsOwner(userID) || r.participantIndex(userID) >= 0 {
+		return ErrAlreadyParticipant
+	}
+	if idx := r.coHostIndex(userID); idx >= 0 {
+		r.CoHosts[idx].Name = name
+		return nil
+	}
+	r.CoHosts = append(r.CoHosts, CoHost{UserID: userID, Name: name, GrantedAt: time.Now()})
+	return nil
+}
+
+// RevokeCoHost takes the co-host rights back from the user. Only the owner can revoke.
+func (r *Round) RevokeCoHost(ownerID, userID string) error {
+	if !r.Can(ownerID, PermGrant) {
+		return ErrNotPermitted
+	}
+	idx := r.coHostIndex(userID)
+	if idx < 0 {
+		return ErrNotCoHost
+	}
+	r.CoHosts = slices.Delete(r.CoHosts, idx, idx+1)
+	return nil
+}
+
+// SetOwnerPlays chooses whether the owner plays instead of moderating; a playing owner cannot see the roles.
+// An owner who already joined cannot go back to moderating, as they would see the others' roles.
+func (r *Round) SetOwnerPlays(ownerID string, plays bool) error {
+	if !r.Can(ownerID, PermGrant) {
+		return ErrNotPermitted
+	}
+	if !plays && r.participantIndex(ownerID) >= 0 {
+		return ErrAlreadyParticipant
+	}
+	r.OwnerPlays = plays
+	return nil
+}
+
+// PlayAsOwner makes the owner play in their own round: the roles are hidden from them until the game ends,
+// and they are dealt an identity like any other player. An owner who already joined keeps their identity.
+// It returns ErrRoundFull if every identity is taken.
+func (r *Round) PlayAsOwner(name, pictureURL string) (Participant, error) {
+	if r.participantIndex(r.OwnerID) < 0 && r.Register(r.OwnerID, name, pictureURL) == "" {
+		return Participant{}, ErrRoundFull
+	}
+	r.OwnerPlays = true
+	return r.Participants[r.participantIndex(r.OwnerID)], nil
+}
+
+// coHostIndex returns the index of the co-host with the user ID, or -1 if the user is not a co-host.
+func (r *Round) coHostIndex(userID string) int {
+	return slices.IndexFunc(r.CoHosts, func(c CoHost) bool { return c.UserID == userID })
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRound_Can(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.SetIdentity("owner", Villager, 2)
+	require.NoError(t, round.GrantCoHost("owner", "host", "Host"))
+	require.NoError(t, round.GrantCoHost("owner", "playing-host", "Playing Host"))
+	round.Register("playing-host", "Playing Host", "")
+
+	perms := []Permission{PermOpenRoom, PermViewRoles, PermReshuffle, PermModerate, PermManage, PermGrant}
+	tests := []struct {
+		userID string
+		want   []Permission
+	}{
+		{"owner", perms},
+		{"host", []Permission{PermOpenRoom, PermViewRoles, PermReshuffle, PermModerate}},
+		{"playing-host", []Permission{PermOpenRoom, PermReshuffle}},
+		{"stranger", nil},
+	}
+
+	for _, tt := range tests {
+		for _, perm := range perms {
+			assert.Equal(t, slices.Contains(tt.want, perm), round.Can(tt.userID, perm), "%s %s", tt.userID, perm)
+		}
+	}
+
+	require.NoError(t, round.SetOwnerPlays("owner", true))
+	assert.False(t, round.Can("owner", PermViewRoles), "A playing owner should not see the roles")
+	assert.False(t, round.Can("owner", PermModerate), "A playing owner should not moderate")
+	assert.True(t, round.Can("owner", PermManage))
+	assert.True(t, round.Can("owner", PermGrant))
+}
+
+func TestRound_GrantAndRevokeCoHost(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.SetIdentity("owner", Villager, 2)
+	round.Register("u1", "U1", "")
+	assert := assert.New(t)
+
+	assert.ErrorIs(round.GrantCoHost("u1", "u2", "U2"), ErrNotPermitted)
+	assert.ErrorIs(round.GrantCoHost("owner", "u1", "U1"), ErrAlreadyParticipant, "Players cannot co-host")
+	assert.ErrorIs(round.GrantCoHost("owner", "owner", "Owner"), ErrAlreadyParticipant)
+
+	assert.NoError(round.GrantCoHost("owner", "u2", "U2"))
+	assert.NoError(round.GrantCoHost("owner", "u2", "Renamed"), "Granting again only renames")
+	require.Len(t, round.CoHosts, 1)
+	assert.Equal("u2", round.CoHosts[0].UserID)
+	assert.Equal("Renamed", round.CoHosts[0].Name)
+	assert.False(round.CoHosts[0].GrantedAt.IsZero())
+	assert.True(round.IsCoHost("u2"))
+	assert.ErrorIs(round.GrantCoHost("u2", "u3", "U3"), ErrNotPermitted, "Co-hosts cannot grant")
+
+	assert.ErrorIs(round.RevokeCoHost("u2", "u2"), ErrNotPermitted)
+	assert.NoError(round.RevokeCoHost("owner", "u2"))
+	assert.False(round.IsCoHost("u2"))
+	assert.ErrorIs(round.RevokeCoHost("owner", "u2"), ErrNotCoHost)
+}
+
+func TestRound_CoHostModerates(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.SetIdentity("owner", Villager, 2)
+	require.NoError(t, round.GrantCoHost("owner", "host", "Host"))
+	round.Register("u1", "U1", "")
+	round.Register("u2", "U2", "")
+	assert := assert.New(t)
+
+	assert.Contains(round.GetParticipantsInfoReplyMessage("host"), "U1:平民")
+	assert.Empty(round.GetParticipantsInfoReplyMessage("u1"))
+	assert.ErrorIs(round.Kick("host", "u1"), ErrNotPermitted, "Co-hosts cannot kick")
+
+	require.NoError(t, round.StartGame("host"))
+	assert.Equal("host", round.Game.ModeratorID)
+}
+
+func TestRound_SetOwnerPlays(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.SetIdentity("owner", Villager, 2)
+	round.Register("u1", "U1", "")
+	assert := assert.New(t)
+
+	assert.ErrorIs(round.SetOwnerPlays("u1", true), ErrNotPermitted)
+	assert.NoError(round.SetOwnerPlays("owner", true))
+	round.Register("owner", "Owner", "")
+	assert.NotContains(round.GetParticipantsInfoReplyMessage("owner"), "平民", "A playing owner should not see the roles")
+	assert.ErrorIs(round.StartGame("owner"), ErrNotPermitted)
+	assert.ErrorIs(round.SetOwnerPlays("owner", false), ErrAlreadyParticipant, "A joined owner cannot go back to moderating")
+}
+
+func TestPermission_String(t *testing.T) {
+	assert.Equal(t, "view roles", PermViewRoles.String())
+	assert.Equal(t, "unknown", Permission(0).String())
+}
+
+func TestRound_PlayAsOwner(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.SetIdentity("owner", Werewolf, 1)
+	round.SetIdentity("owner", Villager, 1)
+	assert := assert.New(t)
+
+	p, err := round.PlayAsOwner("Owner", "url")
+	require.NoError(t, err)
+	assert.True(round.OwnerPlays)
+	assert.Equal("owner", p.UserID)
+	assert.Equal(1, p.Seat)
+	again, err := round.PlayAsOwner("Owner", "url")
+	require.NoError(t, err)
+	assert.Equal(p, again, "An owner who joined keeps their identity")
+
+	// Until the game ends, the owner only learns who joined.
+	info := round.GetParticipantsInfoReplyMessage("owner")
+	assert.Contains(info, "目前參與人數: 1/2")
+	assert.Contains(info, "1號 Owner:已加入")
+	assert.NotContains(info, p.Identity.String())
+
+	round.Register("u1", "U1", "")
+	require.NoError(t, round.GrantCoHost("owner", "host", "Host"))
+	require.NoError(t, round.StartGame("host"))
+	assert.NotContains(round.GetParticipantsInfoReplyMessage("owner"), p.Identity.String(), "The roles stay hidden during the game")
+
+	round.Game.Phase = PhaseEnded
+	assert.Contains(round.GetParticipantsInfoReplyMessage("owner"), "1號 Owner:"+p.Identity.String(), "The roles are revealed once the game ends")
+	assert.Empty(round.GetParticipantsInfoReplyMessage("u1"), "Players never get the table")
+}
+
+func TestRound_PlayAsOwner_Full(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.SetIdentity("owner", Villager, 1)
+	round.Register("u1", "U1", "")
+
+	_, err := round.PlayAsOwner("Owner", "")
+	require.ErrorIs(t, err, ErrRoundFull)
+	assert.False(t, round.OwnerPlays)
+}
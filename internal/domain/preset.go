@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by preset lookups and validation.
+var (
+	ErrPresetNotFound = errors.New("preset not found")
+	ErrPresetInvalid  = errors.New("preset composition does not match its layout")
+)
+
+// Preset is a named board the owner can start a round with, bypassing the LIFF setting page.
+type Preset struct {
+	Key       string           // Key used by the /preset command and postbacks, e.g. "12-wolfking".
+	Name      string           // Display name, e.g. "12人 狼王守衛".
+	Wolves    int              // Declared number of wolves.
+	Gods      int              // Declared number of gods.
+	Villagers int              // Declared number of villagers.
+	Counts    map[Identity]int // {key: identity, value: number of cards}
+}
+
+// presets is the preset catalog, in the order presets are offered.
+var presets = []Preset{
+	{
+		Key: "9-standard", Name: "9人 預女獵", Wolves: 3, Gods: 3, Villagers: 3,
+		Counts: map[Identity]int{Werewolf: 3, Seer: 1, Witch: 1, Hunter: 1, Villager: 3},
+	},
+	{
+		Key: "10-idiot", Name: "10人 預女獵白", Wolves: 3, Gods: 4, Villagers: 3,
+		Counts: map[Identity]int{Werewolf: 3, Seer: 1, Witch: 1, Hunter: 1, Idiot: 1, Villager: 3},
+	},
+	{
+		Key: "12-idiot", Name: "12人 預女獵白", Wolves: 4, Gods: 4, Villagers: 4,
+		Counts: map[Identity]int{Werewolf: 4, Seer: 1, Witch: 1, Hunter: 1, Idiot: 1, Villager: 4},
+	},
+	{
+		Key: "12-guard", Name: "12人 預女獵守", Wolves: 4, Gods: 4, Villagers: 4,
+		Counts: map[Identity]int{Werewolf: 4, Seer: 1, Witch: 1, Hunter: 1, Guard: 1, Villager: 4},
+	},
+	{
+		Key: "12-wolfking", Name: "12人 狼王守衛", Wolves: 4, Gods: 4, Villagers: 4,
+		Counts: map[Identity]int{WerewolfKing: 1, Werewolf: 3, Seer: 1, Witch: 1, Hunter: 1, Guard: 1, Villager: 4},
+	},
+	{
+		Key: "12-whitewolf", Name: "12人 白狼王騎士", Wolves: 4, Gods: 4, Villagers: 4,
+		Counts: map[Identity]int{WhiteWerewolf: 1, Werewolf: 3, Seer: 1, Witch: 1, Guard: 1, Knight: 1, Villager: 4},
+	},
+	{
+		Key: "12-beauty", Name: "12人 狼美人騎士", Wolves: 4, Gods: 4, Villagers: 4,
+		Counts: map[Identity]int{WerewolfBeauty: 1, Werewolf: 3, Seer: 1, Witch: 1, Guard: 1, Knight: 1, Villager: 4},
+	},
+}
+
+// Presets returns every preset in the catalog.
+func Presets() []Preset {
+	return append([]Preset(nil), presets...)
+}
+
+// LookupPreset returns the preset with the given key.
+func LookupPreset(key string) (Preset, error) {
+	for _, p := range presets {
+		if p.Key == key {
+			return p, nil
+		}
+	}
+	return Preset{}, ErrPresetNotFound
+}
+
+// Identities lists the preset's identity cards in role registry order.
+func (p Preset) Identities() []Identity {
+	var identities []Identity
+	for _, r := range roles {
+		for range p.Counts[r.Identity] {
+			identities = append(identities, r.Identity)
+		}
+	}
+	return identities
+}
+
+// Validate checks that the preset is a valid board of registered roles and that its cards match the declared layout.
+func (p Preset) Validate() error {
+	for iden, n := range p.Counts {
+		if _, ok := LookupRole(iden); !ok || n < 1 {
+			return fmt.Errorf("%w: %s has %d of identity %d", ErrPresetInvalid, p.Key, n, iden)
+		}
+	}
+	if _, err := ValidateBoard(p.Counts); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrPresetInvalid, p.Key, err)
+	}
+	counts := CountFactions(p.Identities())
+	if counts[FactionWolf] != p.Wolves || counts[FactionGod] != p.Gods || counts[FactionVillager] != p.Villagers {
+		return fmt.Errorf("%w: %s deals %s", ErrPresetInvalid, p.Key, CompositionSummary(p.Identities()))
+	}
+	return nil
+}
+
+// NewRoundFromPreset creates a round dealing the preset's identities.
+func NewRoundFromPreset(ownerID, inviteNo string, p Preset) *Round {
+	r := NewRound(ownerID, inviteNo)
+	for _, role := range roles {
+		if n := p.Counts[role.Identity]; n > 0 {
+			r.SetIdentity(ownerID, role.Identity, n)
+		}
+	}
+	return r
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresets_Valid(t *testing.T) {
+	keys := make(map[string]bool)
+	for _, p := range Presets() {
+		assert.NoError(t, p.Validate(), "Preset %s", p.Key)
+		warnings, err := ValidateBoard(p.Counts)
+		assert.NoError(t, err)
+		assert.Empty(t, warnings, "Preset %s should be balanced", p.Key)
+		assert.Len(t, p.Identities(), p.Wolves+p.Gods+p.Villagers, "Preset %s", p.Key)
+		assert.False(t, keys[p.Key], "Preset %s registered twice", p.Key)
+		keys[p.Key] = true
+	}
+}
+
+func TestPreset_ValidateMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		preset Preset
+	}{
+		{"wrong wolves", Preset{Key: "x", Wolves: 2, Gods: 1, Villagers: 1, Counts: map[Identity]int{Werewolf: 3, Seer: 1, Villager: 1}}},
+		{"wrong gods", Preset{Key: "x", Wolves: 1, Gods: 2, Villagers: 2, Counts: map[Identity]int{Werewolf: 1, Seer: 1, Villager: 2}}},
+		{"invalid board", Preset{Key: "x", Wolves: 1, Gods: 2, Villagers: 2, Counts: map[Identity]int{Werewolf: 1, Seer: 2, Villager: 2}}},
+		{"unknown identity", Preset{Key: "x", Wolves: 1, Counts: map[Identity]int{Werewolf: 1, Identity(99): 1}}},
+		{"zero count", Preset{Key: "x", Wolves: 1, Counts: map[Identity]int{Werewolf: 1, Seer: 0}}},
+	}
+
+	for _, tt := range tests {
+		assert.ErrorIs(t, tt.preset.Validate(), ErrPresetInvalid, tt.name)
+	}
+}
+
+func TestLookupPreset(t *testing.T) {
+	p, err := LookupPreset("9-standard")
+	require.NoError(t, err)
+	assert.Equal(t, "3狼 3神 3民", CompositionSummary(p.Identities()))
+
+	_, err = LookupPreset("nope")
+	assert.ErrorIs(t, err, ErrPresetNotFound)
+}
+
+func TestNewRoundFromPreset(t *testing.T) {
+	p, err := LookupPreset("12-wolfking")
+	require.NoError(t, err)
+
+	r := NewRoundFromPreset("owner", "000001", p)
+
+	assert.Equal(t, "owner", r.OwnerID)
+	assert.Len(t, r.Identities, 12)
+	assert.ElementsMatch(t, p.Identities(), r.Identities)
+}
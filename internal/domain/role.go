@@ -0,0 +1,84 @@
+package domain
+
+import "strconv"
+
+// Role describes an identity: how it is shown, which faction it plays for and how the LIFF page sends it.
+type Role struct {
+	Identity    Identity // Identity the role describes.
+	Name        string   // Display name.
+	EnglishName string   // English name, used in logs and APIs.
+	Faction     Faction  // Team the role plays for.
+	NightAction bool     // Whether the role acts at night.
+	QueryKey    string   // Query key of the role count sent by the LIFF setting page.
+	Disguised   bool     // Whether the Seer and the bear see the role as good although it is a wolf.
+	Repeatable  bool     // Whether a board may deal more than one card of the role.
+	Weight      int      // Balance weight: how much the role helps the good side, negative for the wolves.
+	Description string   // Short description of the role's ability, shown on the role card.
+	Image       string   // File name of the role art under images/, empty if the role has none.
+}
+
+// roles is the role registry, in the order roles are shown and dealt.
+// Adding a role is a matter of adding an Identity constant and an entry here.
+var roles = []Role{
+	{Identity: WerewolfKing, Name: "狼王", EnglishName: "Werewolf King", Faction: FactionWolf, NightAction: true, QueryKey: "b1", Weight: -8, Description: "每晚與狼隊友一起殺人；出局時（被毒除外）可以開槍帶走一名玩家", Image: "wolf-howl.png"},
+	{Identity: WhiteWerewolf, Name: "白狼王", EnglishName: "White Werewolf", Faction: FactionWolf, NightAction: true, QueryKey: "b2", Weight: -8, Description: "每晚與狼隊友一起殺人；白天可以自爆並帶走一名玩家", Image: "wolf-howl.png"},
+	{Identity: GhostRider, Name: "惡靈騎士", EnglishName: "Ghost Rider", Faction: FactionWolf, NightAction: true, QueryKey: "b3", Weight: -8, Description: "每晚與狼隊友一起殺人；毒藥對他無效，查驗他的預言家會被反傷出局", Image: "wolf-howl.png"},
+	{Identity: WerewolfBeauty, Name: "狼美人", EnglishName: "Werewolf Beauty", Faction: FactionWolf, NightAction: true, QueryKey: "b4", Weight: -8, Description: "每晚與狼隊友一起殺人，並魅惑一名玩家；狼美人出局時被魅惑者隨之殉情", Image: "wolf-howl.png"},
+	{Identity: HiddenWolf, Name: "隱狼", EnglishName: "Hidden Wolf", Faction: FactionWolf, QueryKey: "b5", Disguised: true, Weight: -7, Description: "預言家查驗為好人；狼隊友不知道你的身分，直到狼隊友全部出局才加入殺人", Image: "wolf-howl.png"},
+	{Identity: Werewolf, Name: "狼人", EnglishName: "Werewolf", Faction: FactionWolf, NightAction: true, QueryKey: "b0", Repeatable: true, Weight: -6, Description: "每晚與狼隊友商量殺死一名玩家", Image: "wolf-howl.png"},
+	{Identity: Seer, Name: "預言家", EnglishName: "Seer", Faction: FactionGod, NightAction: true, QueryKey: "g1", Weight: 7, Description: "每晚查驗一名玩家是好人還是狼人"},
+	{Identity: Witch, Name: "女巫", EnglishName: "Witch", Faction: FactionGod, NightAction: true, QueryKey: "g2", Weight: 6, Description: "一瓶解藥救活當晚被殺的玩家，一瓶毒藥毒死一名玩家，兩瓶藥各只能用一次"},
+	{Identity: Hunter, Name: "獵人", EnglishName: "Hunter", Faction: FactionGod, QueryKey: "g3", Weight: 4, Description: "出局時（被毒除外）可以開槍帶走一名玩家"},
+	{Identity: Guard, Name: "守衛", EnglishName: "Guard", Faction: FactionGod, NightAction: true, QueryKey: "g4", Weight: 4, Description: "每晚守護一名玩家免於狼人殺害，不能連續兩晚守護同一人"},
+	{Identity: Knight, Name: "騎士", EnglishName: "Knight", Faction: FactionGod, QueryKey: "g5", Weight: 5, Description: "白天可以翻牌與一名玩家決鬥，對方是狼人則狼人出局，否則騎士出局"},
+	{Identity: Magician, Name: "魔術師", EnglishName: "Magician", Faction: FactionGod, NightAction: true, QueryKey: "g6", Weight: 4, Description: "每晚可以交換兩名玩家的號碼牌，當晚的技能效果隨之交換"},
+	{Identity: Idiot, Name: "白痴", EnglishName: "Idiot", Faction: FactionGod, QueryKey: "g8", Weight: 3, Description: "第一次被投票放逐時翻牌免於出局，但之後失去投票權"},
+	{Identity: BearTamer, Name: "馴熊師", EnglishName: "Bear Tamer", Faction: FactionGod, QueryKey: "g9", Weight: 4, Description: "每天天亮時，若左右鄰座有狼人，熊會咆哮"},
+	{Identity: WildChild, Name: "野孩子", EnglishName: "Wild Child", Faction: FactionVillager, NightAction: true, QueryKey: "g7", Weight: -1, Description: "第一晚選擇一名榜樣，榜樣出局後你就變成狼人"},
+	{Identity: Villager, Name: "平民", EnglishName: "Villager", Faction: FactionVillager, QueryKey: "g0", Repeatable: true, Weight: 1, Description: "沒有特殊能力，靠推理與投票找出狼人"},
+	{Identity: Cupid, Name: "丘比特", EnglishName: "Cupid", Faction: FactionThirdParty, NightAction: true, QueryKey: "t1", Weight: -2, Description: "第一晚連結兩名戀人，戀人同生共死；戀人分屬好人與狼人時，與戀人組成第三方"},
+}
+
+// Roles returns every registered role in display order.
+func Roles() []Role {
+	return append([]Role(nil), roles...)
+}
+
+// LookupRole returns the registered role of an identity.
+func LookupRole(iden Identity) (Role, bool) {
+	for _, r := range roles {
+		if r.Identity == iden {
+			return r, true
+		}
+	}
+	return Role{}, false
+}
+
+// RoleByQueryKey returns the registered role sent under the given LIFF query key.
+func RoleByQueryKey(key string) (Role, bool) {
+	for _, r := range roles {
+		if r.QueryKey == key {
+			return r, true
+		}
+	}
+	return Role{}, false
+}
+
+// CountFactions counts the identities per faction.
+func CountFactions(identities []Identity) map[Faction]int {
+	counts := make(map[Faction]int)
+	for _, iden := range identities {
+		counts[iden.Faction()]++
+	}
+	return counts
+}
+
+// CompositionSummary describes the identities as faction counts, e.g. "3狼 3神 3民".
+func CompositionSummary(identities []Identity) string {
+	counts := CountFactions(identities)
+	summary := strconv.Itoa(counts[FactionWolf]) + "狼 " + strconv.Itoa(counts[FactionGod]) + "神 " + strconv.Itoa(counts[FactionVillager]) + "民"
+	if n := counts[FactionThirdParty]; n > 0 {
+		summary += " " + strconv.Itoa(n) + "第三方"
+	}
+	return summary
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoles_Registry(t *testing.T) {
+	assert := assert.New(t)
+	identities := make(map[Identity]bool)
+	queryKeys := make(map[string]bool)
+
+	for _, r := range Roles() {
+		assert.NotEmpty(r.Name, "Role %d should have a name", r.Identity)
+		assert.NotEmpty(r.EnglishName, "Role %d should have an English name", r.Identity)
+		assert.NotZero(r.Faction, "Role %d should have a faction", r.Identity)
+		assert.False(identities[r.Identity], "Identity %d registered twice", r.Identity)
+		assert.False(queryKeys[r.QueryKey], "Query key %s registered twice", r.QueryKey)
+		identities[r.Identity] = true
+		queryKeys[r.QueryKey] = true
+	}
+
+	// Every identity constant is registered.
+	for iden := WerewolfKing; iden <= BearTamer; iden++ {
+		assert.True(identities[iden], "Identity %d is not registered", iden)
+	}
+}
+
+func TestRoles_ReturnsCopy(t *testing.T) {
+	rs := Roles()
+	rs[0].Name = "changed"
+	assert.NotEqual(t, "changed", Roles()[0].Name)
+}
+
+func TestLookupRole(t *testing.T) {
+	r, ok := LookupRole(Witch)
+	assert.True(t, ok)
+	assert.Equal(t, Role{Identity: Witch, Name: "女巫", EnglishName: "Witch", Faction: FactionGod, NightAction: true, QueryKey: "g2", Weight: 6, Description: "一瓶解藥救活當晚被殺的玩家，一瓶毒藥毒死一名玩家，兩瓶藥各只能用一次"}, r)
+
+	_, ok = LookupRole(Identity(99))
+	assert.False(t, ok)
+}
+
+func TestRoleByQueryKey(t *testing.T) {
+	r, ok := RoleByQueryKey("b0")
+	assert.True(t, ok)
+	assert.Equal(t, Werewolf, r.Identity)
+
+	_, ok = RoleByQueryKey("x9")
+	assert.False(t, ok)
+}
+
+func TestCompositionSummary(t *testing.T) {
+	identities := []Identity{Werewolf, Werewolf, WerewolfKing, Seer, Witch, Hunter, Villager, Villager, Villager}
+	assert.Equal(t, "3狼 3神 3民", CompositionSummary(identities))
+	assert.Equal(t, map[Faction]int{FactionWolf: 3, FactionGod: 3, FactionVillager: 3}, CountFactions(identities))
+}
+
+func TestCompositionSummary_ThirdParty(t *testing.T) {
+	identities := []Identity{Werewolf, HiddenWolf, Seer, Idiot, WildChild, Villager, Cupid}
+	assert.Equal(t, "2狼 2神 2民 1第三方", CompositionSummary(identities))
+}
+
+func TestRoles_Described(t *testing.T) {
+	for _, r := range Roles() {
+		assert.NotEmpty(t, r.Description, "Role %s should describe its ability", r.EnglishName)
+	}
+}
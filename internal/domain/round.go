@@ -1,22 +1,41 @@
 package domain
 
 import (
+	"cmp"
+	"errors"
 	"log" // Using math/rand/v2
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// Errors returned by Round operations.
+var (
+	ErrRegistrationOpen   = errors.New("registration is still open")
+	ErrNotParticipant     = errors.New("not a participant of the round")
+	ErrGameStarted        = errors.New("game already started")
+	ErrSeatInvalid        = errors.New("seat out of range")
+	ErrSeatTaken          = errors.New("seat already taken")
+	ErrAlreadyParticipant = errors.New("already a participant of the round")
+)
+
 // Round represents a game round.
 type Round struct {
-	OwnerID          string        // ID of the user who created the round.
-	InviteNo         string        // Invitation number for the round.
-	CreatedAt        time.Time     // Time when the round was created.
-	ExpiredAt        time.Time     // Time when the round expires.
-	Participants     []Participant // List of participants in the round.
-	Identities       []Identity    // List of identities (roles) assigned in the round.
-	TempIdentity     Identity
-	TempIdentityFlag bool
+	OwnerID          string        `json:"ownerId"`      // ID of the user who created the round.
+	InviteNo         string        `json:"inviteNo"`     // Invitation number for the round.
+	CreatedAt        time.Time     `json:"createdAt"`    // Time when the round was created.
+	ExpiredAt        time.Time     `json:"expiredAt"`    // Time when the round expires.
+	Participants     []Participant `json:"participants"` // List of participants in the round.
+	Identities       []Identity    `json:"identities"`   // List of identities (roles) assigned in the round.
+	TempIdentity     Identity      `json:"tempIdentity"`
+	TempIdentityFlag bool          `json:"tempIdentityFlag"`
+	Rules            Rules         `json:"rules"`                // House rules chosen when the round was created.
+	Game             *Game         `json:"game,omitempty"`       // Game in progress, nil until the owner starts one.
+	GroupID          string        `json:"groupId,omitempty"`    // Group or multi-person chat the round is played in; empty for 1:1 rounds.
+	Replacing        string        `json:"replacing,omitempty"`  // Participant the next user to register replaces, if the owner asked for it.
+	CoHosts          []CoHost      `json:"coHosts,omitempty"`    // Users the owner lets help moderate.
+	OwnerPlays       bool          `json:"ownerPlays,omitempty"` // Whether the owner plays instead of moderating, with the roles hidden from them.
 }
 
 // NewRound creates a new game round.
@@ -29,6 +48,7 @@ func NewRound(userID, inviteNo string) *Round {
 		CreatedAt:        time.Now(),
 		ExpiredAt:        time.Now().Add(2 * time.Hour), // Round expires in 2 hours.
 		TempIdentityFlag: false,
+		Rules:            DefaultRules(),
 	}
 }
 
@@ -52,53 +72,305 @@ func (r *Round) SetIdentity(userID string, iden Identity, nums int) {
 // Register allows a user to join the round.
 // If registration is closed or the user is already registered, it returns an empty string.
 // Otherwise, it assigns an identity to the user and returns the identity as a string.
+// The newcomer takes over the participant the owner is replacing, if any; otherwise the identities are dealt
+// in order, skipping the ones held by participants, so a slot freed by Leave or Kick is dealt again.
 func (r *Round) Register(userID, name, pictureURL string) string {
+	if r.Replacing != "" {
+		if err := r.Replace(r.OwnerID, r.Replacing, userID, name, pictureURL); err == nil {
+			return r.Participants[r.participantIndex(userID)].Identity.String()
+		}
+	}
 	if r.IsRegistrationClose() {
 		log.Println("register is closed")
 		return ""
 	}
 
 	// Assign the next available identity.
-	idx := len(r.Participants)
-	user := NewParticipant(userID, name, pictureURL, r.Identities[idx])
+	iden := r.nextIdentity()
+	user := NewParticipant(userID, name, pictureURL, iden)
+	user.Seat = r.freeSeat()
 	r.Participants = append(r.Participants, *user)
-	return r.Identities[idx].String()
+	return iden.String()
+}
+
+// Leave removes the user from the round before the game starts, freeing their identity and seat.
+func (r *Round) Leave(userID string) error {
+	if r.Game != nil {
+		return ErrGameStarted
+	}
+	idx := r.participantIndex(userID)
+	if idx < 0 {
+		return ErrNotParticipant
+	}
+	r.removeParticipant(idx)
+	return nil
+}
+
+// Kick removes a participant before the game starts, freeing their identity and seat. Only the owner can kick.
+func (r *Round) Kick(ownerID, userID string) error {
+	if !r.Can(ownerID, PermManage) {
+		return ErrNotPermitted
+	}
+	return r.Leave(userID)
+}
+
+// Replace hands a participant's identity and seat over to a newcomer before the game starts. Only the owner can replace.
+func (r *Round) Replace(ownerID, userID, newUserID, name, pictureURL string) error {
+	if !r.Can(ownerID, PermManage) {
+		return ErrNotPermitted
+	}
+	if r.Game != nil {
+		return ErrGameStarted
+	}
+	idx := r.participantIndex(userID)
+	if idx < 0 {
+		return ErrNotParticipant
+	}
+	if r.participantIndex(newUserID) >= 0 {
+		return ErrAlreadyParticipant
+	}
+
+	old := r.Participants[idx]
+	user := NewParticipant(newUserID, name, pictureURL, old.Identity)
+	user.Seat = old.Seat
+	r.Participants[idx] = *user
+	r.Replacing = ""
+	return nil
+}
+
+// StartReplace marks a participant to be replaced by the next user who registers. Only the owner can replace.
+func (r *Round) StartReplace(ownerID, userID string) error {
+	if !r.Can(ownerID, PermManage) {
+		return ErrNotPermitted
+	}
+	if r.Game != nil {
+		return ErrGameStarted
+	}
+	if r.participantIndex(userID) < 0 {
+		return ErrNotParticipant
+	}
+	r.Replacing = userID
+	return nil
+}
+
+// nextIdentity returns the first identity not held by a participant. The caller must check registration is open.
+func (r *Round) nextIdentity() Identity {
+	held := make(map[Identity]int)
+	for _, p := range r.Participants {
+		held[p.Identity]++
+	}
+	for _, iden := range r.Identities {
+		if held[iden] == 0 {
+			return iden
+		}
+		held[iden]--
+	}
+	return 0
+}
+
+// removeParticipant removes the participant at the index, cancelling a pending replacement of them.
+func (r *Round) removeParticipant(idx int) {
+	if r.Participants[idx].UserID == r.Replacing {
+		r.Replacing = ""
+	}
+	r.Participants = slices.Delete(r.Participants, idx, idx+1)
+}
+
+// SetSeat moves a participant to another seat before the game starts.
+// The owner can move anyone, swapping seats with whoever sits there; players can only move themselves to a free seat.
+func (r *Round) SetSeat(requesterID, userID string, seat int) error {
+	if !r.Can(requesterID, PermManage) && requesterID != userID {
+		return ErrNotPermitted
+	}
+	if r.Game != nil {
+		return ErrGameStarted
+	}
+	if seat < 1 || seat > len(r.Identities) {
+		return ErrSeatInvalid
+	}
+	idx := r.participantIndex(userID)
+	if idx < 0 {
+		return ErrNotParticipant
+	}
+
+	if other := r.seatIndex(seat); other >= 0 && other != idx {
+		if !r.Can(requesterID, PermManage) {
+			return ErrSeatTaken
+		}
+		r.Participants[other].Seat = r.Participants[idx].Seat
+	}
+	r.Participants[idx].Seat = seat
+	return nil
+}
+
+// RandomizeSeats deals the seats 1 to n to the n participants who joined, in random order. Only the owner can randomize.
+func (r *Round) RandomizeSeats(ownerID string) error {
+	if !r.Can(ownerID, PermManage) {
+		return ErrNotPermitted
+	}
+	if r.Game != nil {
+		return ErrGameStarted
+	}
+	seats := make([]int, len(r.Participants))
+	for i := range seats {
+		seats[i] = i + 1
+	}
+	if err := Rng.Shuffle(len(seats), func(i, j int) {
+		seats[i], seats[j] = seats[j], seats[i]
+	}); err != nil {
+		return err
+	}
+	for i := range r.Participants {
+		r.Participants[i].Seat = seats[i]
+	}
+	return nil
+}
+
+// SeatedParticipants returns the participants ordered by seat.
+func (r *Round) SeatedParticipants() []Participant {
+	seated := slices.Clone(r.Participants)
+	slices.SortStableFunc(seated, func(a, b Participant) int { return cmp.Compare(a.Seat, b.Seat) })
+	return seated
+}
+
+// freeSeat returns the lowest seat nobody sits in.
+func (r *Round) freeSeat() int {
+	seat := 1
+	for r.seatIndex(seat) >= 0 {
+		seat++
+	}
+	return seat
+}
+
+// seatIndex returns the index of the participant sitting in the seat, or -1 if the seat is free.
+func (r *Round) seatIndex(seat int) int {
+	return slices.IndexFunc(r.Participants, func(p Participant) bool { return p.Seat == seat })
+}
+
+// participantIndex returns the index of the participant with the user ID, or -1 if the user has not joined.
+func (r *Round) participantIndex(userID string) int {
+	return slices.IndexFunc(r.Participants, func(p Participant) bool { return p.UserID == userID })
 }
 
 // Again resets the round for a new game with the same identities.
 // It shuffles identities, clears participants, and extends the expiration time.
+// An owner who plays is dealt a new identity at once, as they were when the round was created.
 func (r *Round) Again() {
 	_ = Rng.Shuffle(len(r.Identities), func(i, j int) {
 		r.Identities[i], r.Identities[j] = r.Identities[j], r.Identities[i]
 	})
-	// Empty participants for the new game.
+	owner, ownerJoined := Participant{}, false
+	if idx := r.participantIndex(r.OwnerID); idx >= 0 {
+		owner, ownerJoined = r.Participants[idx], true
+	}
+	// Empty participants and the finished game for the new game.
 	r.Participants = []Participant{}
+	r.Replacing = ""
+	r.Game = nil
 	// Extend expire time for the new game.
 	r.ExpiredAt = time.Now().Add(2 * time.Hour)
+	if r.OwnerPlays && ownerJoined {
+		r.Register(owner.UserID, owner.Name, owner.PictureURL)
+	}
+}
+
+// StartGame starts the game engine with the registered participants, moderated by the user.
+// Only users who may moderate can start a game, and only once every identity has been taken.
+func (r *Round) StartGame(userID string) error {
+	if !r.Can(userID, PermModerate) {
+		return ErrNotPermitted
+	}
+	if !r.IsRegistrationClose() {
+		return ErrRegistrationOpen
+	}
+	r.Game = NewGame(userID, r.Participants, r.Rules)
+	return nil
+}
+
+// TeamReveal is what a wolf learns about the pack once the round is full.
+type TeamReveal struct {
+	Wolf      Participant   // Wolf receiving the reveal.
+	Teammates []Participant // Other wolves the wolf is allowed to know.
+}
+
+// Teammates returns the participants the user knows as teammates: wolves know their fellow wolves,
+// except those the rules keep hidden. The Hidden Wolf is hidden unless Rules.HiddenWolfRevealed,
+// the Ghost Rider is hidden if Rules.GhostRiderHidden; hidden wolves still know the whole pack.
+// Other roles have no known teammates. Only participants who already joined are listed.
+func (r *Round) Teammates(userID string) []Participant {
+	ok, self := r.IsRegistrationDuplicate(userID)
+	if !ok || self.Identity.Faction() != FactionWolf {
+		return nil
+	}
+
+	var teammates []Participant
+	for _, p := range r.Participants {
+		if p.UserID == userID || p.Identity.Faction() != FactionWolf {
+			continue
+		}
+		if r.hiddenFromPack(p.Identity) && !r.hiddenFromPack(self.Identity) {
+			continue
+		}
+		teammates = append(teammates, p)
+	}
+	return teammates
+}
+
+// TeamReveals lists, for every wolf of the round, the teammates the wolf is allowed to know.
+func (r *Round) TeamReveals() []TeamReveal {
+	var reveals []TeamReveal
+	for _, p := range r.Participants {
+		if p.Identity.Faction() == FactionWolf {
+			reveals = append(reveals, TeamReveal{Wolf: p, Teammates: r.Teammates(p.UserID)})
+		}
+	}
+	return reveals
+}
+
+// hiddenFromPack reports whether the rules keep wolves of the identity unknown to the other wolves.
+func (r *Round) hiddenFromPack(iden Identity) bool {
+	switch iden {
+	case HiddenWolf:
+		return !r.Rules.HiddenWolfRevealed
+	case GhostRider:
+		return r.Rules.GhostRiderHidden
+	}
+	return false
 }
 
 // GetParticipantsInfoReplyMessage returns a string with information about participants.
-// Only the owner can get this information.
-// The string includes the count of participants and their assigned identities.
+// Only the hosts of the round can get this information.
+// The string includes the count of participants and their assigned identities; hosts who play only learn
+// who joined, until the game ends.
 func (r *Round) GetParticipantsInfoReplyMessage(userID string) string {
-	// Check if the user is the owner of the round.
-	if !r.IsOwner(userID) {
+	// Check if the user hosts the round.
+	if !r.Can(userID, PermOpenRoom) {
 		log.Println(r.InviteNo)
-		log.Println(r.OwnerID + " not equal " + userID)
+		log.Println(userID + " does not host " + r.OwnerID)
 		return ""
 	}
+	rolesVisible := r.Can(userID, PermViewRoles)
 
 	var sb strings.Builder
 	sb.WriteString("目前參與人數: ") // "Current number of participants: "
 	sb.WriteString(strconv.Itoa(len(r.Participants)))
 	sb.WriteString("/")
 	sb.WriteString(strconv.Itoa(len(r.Identities)))
+	sb.WriteString("\n配置: ")
+	sb.WriteString(CompositionSummary(r.Identities))
 
-	for _, p := range r.Participants {
+	for _, p := range r.SeatedParticipants() {
 		sb.WriteString("\n")
-		sb.WriteString(p.Name)
+		sb.WriteString(p.Label())
 		sb.WriteString(":")
-		sb.WriteString(p.Identity.String())
+		if rolesVisible {
+			sb.WriteString(p.Identity.String())
+		} else {
+			sb.WriteString("已加入")
+		}
+	}
+	if !rolesVisible {
+		sb.WriteString("\n身分將在遊戲結束後公開")
 	}
 	return sb.String()
 }
@@ -129,6 +401,11 @@ func (r *Round) IsOwner(creatorID string) bool {
 	return r.OwnerID == creatorID
 }
 
+// GameEnded reports whether the game engine ran the game of the round to its end.
+func (r *Round) GameEnded() bool {
+	return r.Game != nil && r.Game.Phase == PhaseEnded
+}
+
 // IsExpired checks if the round has expired.
 func (r *Round) IsExpired() bool {
 	return r.ExpiredAt.Before(time.Now())
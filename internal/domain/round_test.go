@@ -116,8 +116,9 @@ func TestRound_GetParticipantsInfoReplyMessage(t *testing.T) {
 	info := round.GetParticipantsInfoReplyMessage(ownerID)
 	expectedPrefix := "目前參與人數: 1/2"
 	assert.Truef(strings.HasPrefix(info, expectedPrefix), "Info should start with %s, got %s", expectedPrefix, info)
+	assert.Contains(info, "配置: 1狼 0神 1民", "Info should contain the composition")
 	if len(round.Participants) > 0 {
-		assert.Contains(info, "User One:"+round.Participants[0].Identity.String(), "Info should contain participant details")
+		assert.Contains(info, "1號 User One:"+round.Participants[0].Identity.String(), "Info should contain participant details")
 	}
 
 	// Test by non-owner
@@ -125,6 +126,183 @@ func TestRound_GetParticipantsInfoReplyMessage(t *testing.T) {
 	assert.Empty(infoNonOwner, "Expected empty string for non-owner")
 }
 
+func TestRound_Kick(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.Identities = []Identity{Werewolf, Seer, Witch, Villager}
+	round.Register("u1", "U1", "")
+	round.Register("u2", "U2", "")
+	round.Register("u3", "U3", "")
+	assert := assert.New(t)
+
+	assert.ErrorIs(round.Kick("u1", "u2"), ErrNotPermitted)
+	assert.ErrorIs(round.Kick("owner", "stranger"), ErrNotParticipant)
+
+	assert.NoError(round.Kick("owner", "u2"))
+	assert.Len(round.Participants, 2)
+	assert.Equal([]Identity{Werewolf, Seer, Witch, Villager}, round.Identities, "Kicking must not change the board")
+
+	// The freed identity and seat are dealt again, never an identity already taken.
+	round.Register("u4", "U4", "")
+	assert.Equal(Seer, round.Participants[2].Identity)
+	assert.Equal(2, round.Participants[2].Seat)
+	round.Register("u5", "U5", "")
+	assert.Equal(Villager, round.Participants[3].Identity)
+	assert.NoError(round.StartGame("owner"))
+	assert.ErrorIs(round.Kick("owner", "u1"), ErrGameStarted)
+}
+
+func TestRound_Leave(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.Identities = []Identity{Werewolf, Seer, Villager, Villager}
+	round.Register("u1", "U1", "")
+	round.Register("u2", "U2", "")
+	round.Register("u3", "U3", "")
+	assert := assert.New(t)
+
+	assert.ErrorIs(round.Leave("stranger"), ErrNotParticipant)
+	assert.NoError(round.Leave("u1"), "Leaving from the middle of the list")
+	assert.NoError(round.Leave("u3"))
+
+	// Every card is dealt exactly once however players come and go.
+	round.Register("u4", "U4", "")
+	round.Register("u5", "U5", "")
+	round.Register("u6", "U6", "")
+	assert.True(round.IsRegistrationClose())
+	assert.Empty(round.Register("u7", "U7", ""))
+	var dealt []Identity
+	for _, p := range round.Participants {
+		dealt = append(dealt, p.Identity)
+	}
+	assert.ElementsMatch(round.Identities, dealt)
+
+	assert.NoError(round.StartGame("owner"))
+	assert.ErrorIs(round.Leave("u2"), ErrGameStarted)
+}
+
+func TestRound_Replace(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.Identities = []Identity{Werewolf, Seer}
+	round.Register("u1", "U1", "")
+	round.Register("u2", "U2", "")
+	assert := assert.New(t)
+	wolf := round.Participants[0]
+
+	assert.ErrorIs(round.Replace("u2", "u1", "u3", "U3", ""), ErrNotPermitted)
+	assert.ErrorIs(round.Replace("owner", "stranger", "u3", "U3", ""), ErrNotParticipant)
+	assert.ErrorIs(round.Replace("owner", "u1", "u2", "U2", ""), ErrAlreadyParticipant)
+
+	assert.NoError(round.Replace("owner", "u1", "u3", "U3", "url3"))
+	assert.Equal("u3", round.Participants[0].UserID)
+	assert.Equal(wolf.Identity, round.Participants[0].Identity, "The newcomer takes over the identity")
+	assert.Equal(wolf.Seat, round.Participants[0].Seat, "The newcomer takes over the seat")
+
+	// A replacement the owner asked for is taken by the next user who registers, even in a full round.
+	assert.ErrorIs(round.StartReplace("u2", "u2"), ErrNotPermitted)
+	assert.NoError(round.StartReplace("owner", "u2"))
+	assert.Equal(Seer.String(), round.Register("u4", "U4", ""))
+	assert.Equal("u4", round.Participants[1].UserID)
+	assert.Empty(round.Replacing)
+	assert.Empty(round.Register("u5", "U5", ""), "Only one user replaces the participant")
+
+	assert.NoError(round.StartReplace("owner", "u4"))
+	assert.NoError(round.Kick("owner", "u4"))
+	assert.Empty(round.Replacing, "Kicking cancels the replacement")
+}
+
+func TestRound_Seats(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.Identities = []Identity{Werewolf, Seer, Witch, Villager}
+	round.Register("u1", "U1", "")
+	round.Register("u2", "U2", "")
+	round.Register("u3", "U3", "")
+	assert := assert.New(t)
+
+	assert.Equal(1, round.Participants[0].Seat)
+	assert.Equal(3, round.Participants[2].Seat)
+	assert.Equal("2號 U2", round.Participants[1].Label())
+
+	// Players pick free seats only.
+	assert.ErrorIs(round.SetSeat("u1", "u2", 4), ErrNotPermitted)
+	assert.ErrorIs(round.SetSeat("u1", "u1", 2), ErrSeatTaken)
+	assert.ErrorIs(round.SetSeat("u1", "u1", 5), ErrSeatInvalid)
+	assert.ErrorIs(round.SetSeat("stranger", "stranger", 4), ErrNotParticipant)
+	assert.NoError(round.SetSeat("u1", "u1", 4))
+	assert.Equal(4, round.Participants[0].Seat)
+
+	// The next player to join takes the lowest free seat.
+	round.Register("u4", "U4", "")
+	assert.Equal(1, round.Participants[3].Seat)
+
+	// The owner swaps seats.
+	assert.NoError(round.SetSeat("owner", "u4", 3))
+	assert.Equal(3, round.Participants[3].Seat)
+	assert.Equal(1, round.Participants[2].Seat)
+	var order []string
+	for _, p := range round.SeatedParticipants() {
+		order = append(order, p.UserID)
+	}
+	assert.Equal([]string{"u3", "u2", "u4", "u1"}, order)
+
+	assert.ErrorIs(round.RandomizeSeats("u1"), ErrNotPermitted)
+	assert.NoError(round.RandomizeSeats("owner"))
+	var seats []int
+	for _, p := range round.Participants {
+		seats = append(seats, p.Seat)
+	}
+	assert.ElementsMatch([]int{1, 2, 3, 4}, seats)
+
+	assert.NoError(round.StartGame("owner"))
+	assert.ErrorIs(round.SetSeat("owner", "u1", 2), ErrGameStarted)
+	assert.ErrorIs(round.RandomizeSeats("owner"), ErrGameStarted)
+	for i := 1; i < len(round.Game.Players); i++ {
+		assert.Less(round.Game.Players[i-1].Seat, round.Game.Players[i].Seat, "Players should sit in seat order")
+	}
+}
+
+func TestRound_Teammates(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.Participants = []Participant{
+		{UserID: "king", Name: "King", Identity: WerewolfKing},
+		{UserID: "wolf", Name: "Wolf", Identity: Werewolf},
+		{UserID: "hidden", Name: "Hidden", Identity: HiddenWolf},
+		{UserID: "seer", Name: "Seer", Identity: Seer},
+	}
+	assert := assert.New(t)
+
+	names := func(ps []Participant) []string {
+		var names []string
+		for _, p := range ps {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+	assert.Equal([]string{"Wolf"}, names(round.Teammates("king")), "Wolves should not see the Hidden Wolf")
+	assert.Equal([]string{"King", "Wolf"}, names(round.Teammates("hidden")), "The Hidden Wolf should see every wolf")
+	assert.Empty(round.Teammates("seer"), "Good roles have no known teammates")
+	assert.Empty(round.Teammates("stranger"))
+
+	round.Rules.HiddenWolfRevealed = true
+	assert.Equal([]string{"Wolf", "Hidden"}, names(round.Teammates("king")), "Revealed Hidden Wolf should be known")
+}
+
+func TestRound_TeamReveals(t *testing.T) {
+	round := NewRound("owner", "000001")
+	round.Rules.GhostRiderHidden = true
+	round.Participants = []Participant{
+		{UserID: "rider", Name: "Rider", Identity: GhostRider},
+		{UserID: "wolf", Name: "Wolf", Identity: Werewolf},
+		{UserID: "villager", Name: "Villager", Identity: Villager},
+	}
+	assert := assert.New(t)
+
+	reveals := round.TeamReveals()
+	assert.Len(reveals, 2, "Only wolves get a reveal")
+	assert.Equal("rider", reveals[0].Wolf.UserID)
+	assert.Len(reveals[0].Teammates, 1, "The hidden Ghost Rider still knows the pack")
+	assert.Equal("wolf", reveals[1].Wolf.UserID)
+	assert.Empty(reveals[1].Teammates, "The Ghost Rider should be hidden from the pack")
+}
+
 func TestRound_IsRegistrationClose(t *testing.T) {
 	round := NewRound("owner123", "testInvite")
 	round.SetIdentity("owner123", Villager, 1)
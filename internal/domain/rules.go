@@ -0,0 +1,39 @@
+package domain
+
+// TieRule decides what happens when the day vote ends in a tie.
+type TieRule int
+
+// Constants for the tie rules.
+const (
+	TieRevote  TieRule = iota + 1 // Re-vote among the tied players; a second tie exiles nobody.
+	TieNoExile                    // Nobody is exiled on a tie.
+)
+
+// String returns the string representation of a TieRule.
+func (t TieRule) String() string {
+	switch t {
+	case TieRevote:
+		return "平票PK"
+	case TieNoExile:
+		return "平票無人放逐"
+	default:
+		return "unknown"
+	}
+}
+
+// Rules are the house rules chosen when a round is created.
+type Rules struct {
+	TieRule TieRule `json:"tieRule"` // How a tied day vote is resolved.
+	WinRule WinRule `json:"winRule"` // When the wolves win.
+
+	HiddenWolfRevealed bool `json:"hiddenWolfRevealed,omitempty"` // Whether the other wolves know the Hidden Wolf.
+	GhostRiderHidden   bool `json:"ghostRiderHidden,omitempty"`   // Whether the Ghost Rider stays unknown to the other wolves.
+}
+
+// DefaultRules returns the rules used when the owner does not choose any.
+func DefaultRules() Rules {
+	return Rules{
+		TieRule: TieRevote,
+		WinRule: WinSideKill,
+	}
+}
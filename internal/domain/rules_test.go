@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRules(t *testing.T) {
+	assert.Equal(t, TieRevote, DefaultRules().TieRule)
+	assert.Equal(t, WinSideKill, DefaultRules().WinRule)
+}
+
+func TestTieRule_String(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("平票PK", TieRevote.String())
+	assert.Equal("平票無人放逐", TieNoExile.String())
+	assert.Equal("unknown", TieRule(99).String())
+}
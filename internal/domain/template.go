@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Errors returned when creating a board template.
+var (
+	ErrTemplateNameInvalid = errors.New("template name must be 1 to 20 characters")
+	ErrTemplateEmpty       = errors.New("template has no identities")
+)
+
+// maxTemplateNameLen is the maximum number of characters in a template name.
+const maxTemplateNameLen = 20
+
+// BoardTemplate is a board composition an owner saved under a name to start rounds with later.
+type BoardTemplate struct {
+	OwnerID    string     `json:"ownerId"`    // User who saved the template.
+	Name       string     `json:"name"`       // Name chosen by the owner, unique per owner.
+	Identities []Identity `json:"identities"` // Identity cards in role registry order.
+	Rules      Rules      `json:"rules"`      // House rules of the board.
+	UpdatedAt  time.Time  `json:"updatedAt"`  // Time the template was last saved.
+}
+
+// NewBoardTemplate creates a template of the given identities and rules.
+// The identities are copied and sorted in role registry order, so the same board always looks the same.
+func NewBoardTemplate(ownerID, name string, identities []Identity, rules Rules) (*BoardTemplate, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxTemplateNameLen {
+		return nil, ErrTemplateNameInvalid
+	}
+	if len(identities) == 0 {
+		return nil, ErrTemplateEmpty
+	}
+
+	sorted := slices.Clone(identities)
+	slices.SortStableFunc(sorted, func(a, b Identity) int {
+		return roleIndex(a) - roleIndex(b)
+	})
+	return &BoardTemplate{
+		OwnerID:    ownerID,
+		Name:       name,
+		Identities: sorted,
+		Rules:      rules,
+		UpdatedAt:  time.Now(),
+	}, nil
+}
+
+// Summary describes the template's board, e.g. "3狼 3神 3民".
+func (t *BoardTemplate) Summary() string {
+	return CompositionSummary(t.Identities)
+}
+
+// NewRound creates a round for the template's owner dealing the template's board.
+func (t *BoardTemplate) NewRound(inviteNo string) *Round {
+	r := NewRound(t.OwnerID, inviteNo)
+	r.Rules = t.Rules
+	for _, iden := range t.Identities {
+		r.SetIdentity(t.OwnerID, iden, 1)
+	}
+	return r
+}
+
+// roleIndex returns the position of an identity in the role registry, or len(roles) if unregistered.
+func roleIndex(iden Identity) int {
+	for i, r := range roles {
+		if r.Identity == iden {
+			return i
+		}
+	}
+	return len(roles)
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoardTemplate(t *testing.T) {
+	assert := assert.New(t)
+	identities := []Identity{Villager, Werewolf, Seer, Villager, Werewolf}
+	rules := Rules{TieRule: TieNoExile, WinRule: WinAllKill}
+
+	tmpl, err := NewBoardTemplate("owner", "  週五團  ", identities, rules)
+	require.NoError(t, err)
+
+	assert.Equal("owner", tmpl.OwnerID)
+	assert.Equal("週五團", tmpl.Name)
+	assert.Equal([]Identity{Werewolf, Werewolf, Seer, Villager, Villager}, tmpl.Identities, "Identities follow registry order")
+	assert.Equal([]Identity{Villager, Werewolf, Seer, Villager, Werewolf}, identities, "Input must not be modified")
+	assert.Equal(rules, tmpl.Rules)
+	assert.Equal("2狼 1神 2民", tmpl.Summary())
+}
+
+func TestNewBoardTemplate_Invalid(t *testing.T) {
+	_, err := NewBoardTemplate("owner", " ", []Identity{Werewolf}, DefaultRules())
+	assert.ErrorIs(t, err, ErrTemplateNameInvalid)
+
+	_, err = NewBoardTemplate("owner", strings.Repeat("狼", 21), []Identity{Werewolf}, DefaultRules())
+	assert.ErrorIs(t, err, ErrTemplateNameInvalid)
+
+	_, err = NewBoardTemplate("owner", "empty", nil, DefaultRules())
+	assert.ErrorIs(t, err, ErrTemplateEmpty)
+}
+
+func TestBoardTemplate_NewRound(t *testing.T) {
+	tmpl, err := NewBoardTemplate("owner", "board", []Identity{Werewolf, Seer, Villager}, Rules{TieRule: TieNoExile, WinRule: WinSideKill})
+	require.NoError(t, err)
+
+	r := tmpl.NewRound("000001")
+
+	assert.Equal(t, "owner", r.OwnerID)
+	assert.Equal(t, "000001", r.InviteNo)
+	assert.ElementsMatch(t, tmpl.Identities, r.Identities)
+	assert.Equal(t, tmpl.Rules, r.Rules)
+}
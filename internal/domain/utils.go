@@ -3,8 +3,17 @@ package domain
 import (
 	"crypto/rand"
 	"math/big"
+	mrand "math/rand/v2"
 )
 
+// Shuffler is the source of randomness used to deal identities.
+type Shuffler interface {
+	// Shuffle shuffles a collection of n elements, calling swap to exchange elements i and j.
+	Shuffle(n int, swap func(i, j int)) error
+	// IntN returns a random integer in [0, n).
+	IntN(n int) (int, error)
+}
+
 // cryptoRandShuffler provides a Shuffle method using crypto/rand.
 type cryptoRandShuffler struct{}
 
@@ -57,6 +66,31 @@ func (e *invalidArgumentError) Error() string {
 	return e.message
 }
 
-// Rng is an instance of cryptoRandShuffler.
-// Calls to Rng.Shuffle will now use the crypto/rand-based implementation.
-var Rng = &cryptoRandShuffler{}
+// seededShuffler provides a deterministic Shuffle method for tests and replays.
+type seededShuffler struct {
+	rng *mrand.Rand
+}
+
+// NewSeededShuffler returns a Shuffler that yields the same sequence for the same seed.
+// It is not cryptographically secure and must not be used to deal real games.
+func NewSeededShuffler(seed uint64) Shuffler {
+	return &seededShuffler{rng: mrand.New(mrand.NewPCG(seed, seed))} //nolint:gosec // Deterministic by design.
+}
+
+// Shuffle shuffles a collection of n elements using the seeded generator.
+func (s *seededShuffler) Shuffle(n int, swap func(i, j int)) error {
+	s.rng.Shuffle(n, swap)
+	return nil
+}
+
+// IntN returns a deterministic random integer in [0, n).
+func (s *seededShuffler) IntN(n int) (int, error) {
+	if n <= 0 {
+		return 0, &invalidArgumentError{message: "argument to IntN must be positive"}
+	}
+	return s.rng.IntN(n), nil
+}
+
+// Rng is the Shuffler used to deal identities, an instance of cryptoRandShuffler by default.
+// Tests may replace it with NewSeededShuffler for deterministic deals.
+var Rng Shuffler = &cryptoRandShuffler{}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Constants for the player count limits of a board.
+const (
+	MinPlayers = 4  // Fewest cards a board may deal.
+	MaxPlayers = 18 // Most cards a board may deal.
+)
+
+// IssueCode identifies a problem found by ValidateBoard.
+type IssueCode int
+
+// Constants for the board issues. Codes before IssueWolvesTooMany are errors; the rest are warnings.
+const (
+	IssueTooFewPlayers  IssueCode = iota + 1 // Fewer cards than MinPlayers.
+	IssueTooManyPlayers                      // More cards than MaxPlayers.
+	IssueNegativeCount                       // A role has a negative count.
+	IssueUnknownRole                         // An identity is not in the role registry.
+	IssueNoWolves                            // Nobody plays for the wolves.
+	IssueNoGood                              // Nobody plays for the good side.
+	IssueUniqueRole                          // A unique role is dealt more than once.
+	IssueWolvesTooMany                       // Warning: more than a third of the players are wolves.
+	IssueWolvesTooFew                        // Warning: fewer than a fifth of the players are wolves.
+	IssueNoGods                              // Warning: the good side has no gods.
+)
+
+// String returns the string representation of an IssueCode.
+func (c IssueCode) String() string {
+	switch c {
+	case IssueTooFewPlayers:
+		return "too_few_players"
+	case IssueTooManyPlayers:
+		return "too_many_players"
+	case IssueNegativeCount:
+		return "negative_count"
+	case IssueUnknownRole:
+		return "unknown_role"
+	case IssueNoWolves:
+		return "no_wolves"
+	case IssueNoGood:
+		return "no_good"
+	case IssueUniqueRole:
+		return "unique_role"
+	case IssueWolvesTooMany:
+		return "wolves_too_many"
+	case IssueWolvesTooFew:
+		return "wolves_too_few"
+	case IssueNoGods:
+		return "no_gods"
+	default:
+		return "unknown"
+	}
+}
+
+// Issue is a problem of a board, with a message to show the owner.
+type Issue struct {
+	Code     IssueCode `json:"code"`               // Kind of problem.
+	Identity Identity  `json:"identity,omitempty"` // Role concerned, if any.
+	Message  string    `json:"message"`            // Explanation for the owner, in Chinese.
+}
+
+// BoardError lists every reason a board cannot be dealt.
+type BoardError struct {
+	Issues []Issue
+}
+
+// Error joins the messages of the issues.
+func (e *BoardError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, i := range e.Issues {
+		messages = append(messages, i.Message)
+	}
+	return "invalid board: " + strings.Join(messages, "; ")
+}
+
+// ValidateBoard checks a board given as role counts.
+// It returns a *BoardError listing every problem that prevents dealing the board,
+// and otherwise the warnings about boards that can be dealt but are likely unbalanced.
+func ValidateBoard(counts map[Identity]int) ([]Issue, error) {
+	var issues []Issue
+	total := 0
+	factions := make(map[Faction]int)
+
+	// Registered roles in registry order, so the issues come out in a stable order.
+	for _, r := range roles {
+		n := counts[r.Identity]
+		switch {
+		case n < 0:
+			issues = append(issues, Issue{Code: IssueNegativeCount, Identity: r.Identity, Message: r.Name + " 的數量不可為負數"})
+			continue
+		case n > 1 && !r.Repeatable:
+			issues = append(issues, Issue{Code: IssueUniqueRole, Identity: r.Identity, Message: r.Name + " 只能有 1 名，目前 " + strconv.Itoa(n) + " 名"})
+		}
+		total += n
+		factions[r.Faction] += n
+	}
+	for iden, n := range counts {
+		if _, ok := LookupRole(iden); !ok && n != 0 {
+			issues = append(issues, Issue{Code: IssueUnknownRole, Identity: iden, Message: "未知的角色 (編號 " + strconv.Itoa(int(iden)) + ")"})
+		}
+	}
+
+	if total < MinPlayers {
+		issues = append(issues, Issue{Code: IssueTooFewPlayers, Message: "人數至少需要 " + strconv.Itoa(MinPlayers) + " 人，目前 " + strconv.Itoa(total) + " 人"})
+	}
+	if total > MaxPlayers {
+		issues = append(issues, Issue{Code: IssueTooManyPlayers, Message: "人數最多 " + strconv.Itoa(MaxPlayers) + " 人，目前 " + strconv.Itoa(total) + " 人"})
+	}
+	if factions[FactionWolf] == 0 {
+		issues = append(issues, Issue{Code: IssueNoWolves, Message: "至少需要 1 名狼人陣營角色"})
+	}
+	if factions[FactionGod]+factions[FactionVillager] == 0 {
+		issues = append(issues, Issue{Code: IssueNoGood, Message: "至少需要 1 名好人陣營角色"})
+	}
+	if len(issues) > 0 {
+		return nil, &BoardError{Issues: issues}
+	}
+
+	var warnings []Issue
+	wolves := factions[FactionWolf]
+	ratio := strconv.Itoa(wolves) + "狼 / " + strconv.Itoa(total) + "人"
+	if wolves*3 > total {
+		warnings = append(warnings, Issue{Code: IssueWolvesTooMany, Message: "狼人比例偏高 (" + ratio + ")"})
+	}
+	if wolves*5 < total {
+		warnings = append(warnings, Issue{Code: IssueWolvesTooFew, Message: "狼人比例偏低 (" + ratio + ")"})
+	}
+	if factions[FactionGod] == 0 {
+		warnings = append(warnings, Issue{Code: IssueNoGods, Message: "沒有神職，好人陣營可能難以獲勝"})
+	}
+	return warnings, nil
+}
+
+// IdentityCounts counts the cards of each identity.
+func IdentityCounts(identities []Identity) map[Identity]int {
+	counts := make(map[Identity]int)
+	for _, iden := range identities {
+		counts[iden]++
+	}
+	return counts
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBoard_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[Identity]int
+		want   []IssueCode
+	}{
+		{"too few", map[Identity]int{Werewolf: 1, Villager: 2}, []IssueCode{IssueTooFewPlayers}},
+		{"too many", map[Identity]int{Werewolf: 6, Villager: 13}, []IssueCode{IssueTooManyPlayers}},
+		{"negative", map[Identity]int{Werewolf: 2, Seer: 1, Villager: -3}, []IssueCode{IssueNegativeCount, IssueTooFewPlayers}},
+		{"unknown", map[Identity]int{Werewolf: 2, Villager: 3, Identity(99): 1}, []IssueCode{IssueUnknownRole}},
+		{"no wolves", map[Identity]int{Seer: 1, Villager: 4}, []IssueCode{IssueNoWolves}},
+		{"no good", map[Identity]int{Werewolf: 3, WerewolfKing: 1, Cupid: 1}, []IssueCode{IssueNoGood}},
+		{"two witches", map[Identity]int{Werewolf: 2, Witch: 2, Villager: 2}, []IssueCode{IssueUniqueRole}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := ValidateBoard(tt.counts)
+			assert.Nil(t, warnings)
+
+			var boardErr *BoardError
+			require.ErrorAs(t, err, &boardErr)
+			var codes []IssueCode
+			for _, i := range boardErr.Issues {
+				codes = append(codes, i.Code)
+				assert.NotEmpty(t, i.Message)
+			}
+			assert.Equal(t, tt.want, codes)
+		})
+	}
+}
+
+func TestValidateBoard_Messages(t *testing.T) {
+	_, err := ValidateBoard(map[Identity]int{Witch: 2, Villager: 1})
+
+	var boardErr *BoardError
+	require.ErrorAs(t, err, &boardErr)
+	assert.Equal(t, []Issue{
+		{Code: IssueUniqueRole, Identity: Witch, Message: "女巫 只能有 1 名，目前 2 名"},
+		{Code: IssueTooFewPlayers, Message: "人數至少需要 4 人，目前 3 人"},
+		{Code: IssueNoWolves, Message: "至少需要 1 名狼人陣營角色"},
+	}, boardErr.Issues)
+	assert.Contains(t, err.Error(), "女巫 只能有 1 名")
+}
+
+func TestValidateBoard_Warnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[Identity]int
+		want   []IssueCode
+	}{
+		{"balanced", map[Identity]int{Werewolf: 3, Seer: 1, Witch: 1, Hunter: 1, Villager: 3}, nil},
+		{"too many wolves", map[Identity]int{Werewolf: 3, Seer: 1, Villager: 2}, []IssueCode{IssueWolvesTooMany}},
+		{"too few wolves", map[Identity]int{Werewolf: 1, Seer: 1, Witch: 1, Villager: 4}, []IssueCode{IssueWolvesTooFew}},
+		{"no gods", map[Identity]int{Werewolf: 2, Villager: 4}, []IssueCode{IssueNoGods}},
+	}
+
+	for _, tt := range tests {
+		warnings, err := ValidateBoard(tt.counts)
+		require.NoError(t, err, tt.name)
+		var codes []IssueCode
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		assert.Equal(t, tt.want, codes, tt.name)
+	}
+}
+
+func TestIdentityCounts(t *testing.T) {
+	assert.Equal(t, map[Identity]int{Werewolf: 2, Seer: 1}, IdentityCounts([]Identity{Werewolf, Seer, Werewolf}))
+}
@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Ballot is an open day vote.
+type Ballot struct {
+	Candidates []string          `json:"candidates"` // Players who can be voted for, in seating order.
+	Votes      map[string]string `json:"votes"`      // {key: voterID, value: targetID or "" for abstain}
+	Runoff     bool              `json:"runoff"`     // Whether this is a re-vote among tied players.
+}
+
+// VoteRecord is the outcome of a finished day vote.
+type VoteRecord struct {
+	Day    int               `json:"day"`    // Day of the vote.
+	Runoff bool              `json:"runoff"` // Whether it was a re-vote among tied players.
+	Votes  map[string]string `json:"votes"`  // {key: voterID, value: targetID or "" for abstain}
+	Exiled string            `json:"exiled"` // Exiled player, empty if nobody.
+	Tied   []string          `json:"tied"`   // Players tied for the most votes, if any.
+}
+
+// openVote starts a ballot among the candidates, or among every living player if candidates is nil.
+func (g *Game) openVote(candidates []string) {
+	runoff := candidates != nil
+	if !runoff {
+		for _, p := range g.AlivePlayers() {
+			candidates = append(candidates, p.UserID)
+		}
+	}
+	g.Phase = PhaseVote
+	g.Ballot = &Ballot{
+		Candidates: candidates,
+		Votes:      make(map[string]string),
+		Runoff:     runoff,
+	}
+	g.Turn++
+}
+
+// Voters returns the players who may vote in the open ballot.
+// Tied players do not vote in a re-vote, and a revealed Idiot never votes again.
+func (g *Game) Voters() []Player {
+	if g.Ballot == nil {
+		return nil
+	}
+	var voters []Player
+	for _, p := range g.AlivePlayers() {
+		if (g.Ballot.Runoff && g.isCandidate(p.UserID)) || p.Revealed {
+			continue
+		}
+		voters = append(voters, p)
+	}
+	return voters
+}
+
+// castVote records a vote and tallies the ballot once every voter has voted.
+func (g *Game) castVote(voterID, targetID string) ([]Notice, error) {
+	if !g.isVoter(voterID) {
+		return nil, ErrNotYourTurn
+	}
+	if _, ok := g.Ballot.Votes[voterID]; ok {
+		return nil, ErrAlreadyVoted
+	}
+	if targetID != "" && (targetID == voterID || !g.isCandidate(targetID)) {
+		return nil, ErrInvalidTarget
+	}
+
+	g.Ballot.Votes[voterID] = targetID
+	if len(g.Ballot.Votes) < len(g.Voters()) {
+		return nil, nil
+	}
+	return g.tally(), nil
+}
+
+// tally counts the open ballot, records it and resolves the exile or a re-vote.
+func (g *Game) tally() []Notice {
+	counts := make(map[string]int)
+	most := 0
+	for _, target := range g.Ballot.Votes {
+		if target == "" {
+			continue
+		}
+		counts[target]++
+		most = max(most, counts[target])
+	}
+	var top []string
+	for _, id := range g.Ballot.Candidates {
+		if most > 0 && counts[id] == most {
+			top = append(top, id)
+		}
+	}
+
+	record := VoteRecord{Day: g.Day, Runoff: g.Ballot.Runoff, Votes: g.Ballot.Votes}
+	notices := []Notice{{Text: g.voteSummary(counts)}}
+
+	switch {
+	case len(top) == 1:
+		record.Exiled = top[0]
+		g.VoteHistory = append(g.VoteHistory, record)
+		return append(notices, g.exile(top[0])...)
+	case len(top) == 0:
+		g.VoteHistory = append(g.VoteHistory, record)
+		return append(notices, g.exile("")...)
+	}
+
+	record.Tied = top
+	g.VoteHistory = append(g.VoteHistory, record)
+	// Everyone alive being tied leaves nobody to vote in a re-vote.
+	if g.Rules.TieRule != TieNoExile && !g.Ballot.Runoff && len(top) < len(g.AlivePlayers()) {
+		g.openVote(top)
+		return append(notices, Notice{Text: "平票，請對 " + g.names(top) + " 重新投票"})
+	}
+	return append(notices, g.exile("")...)
+}
+
+// ballotPrompts returns a ballot for every voter who has not voted yet.
+func (g *Game) ballotPrompts() []Prompt {
+	var prompts []Prompt
+	for _, voter := range g.Voters() {
+		if _, ok := g.Ballot.Votes[voter.UserID]; ok {
+			continue
+		}
+		prompt := Prompt{UserID: voter.UserID, Text: "請投票選出要放逐的玩家"}
+		if g.Ballot.Runoff {
+			prompt.Text = "平票PK，請重新投票"
+		}
+		for _, id := range g.Ballot.Candidates {
+			if id == voter.UserID {
+				continue
+			}
+			p, _ := g.Player(id)
+			prompt.Options = append(prompt.Options, Option{Label: p.Label(), Action: ActionVote, Target: id})
+		}
+		prompt.Options = append(prompt.Options, Option{Label: "棄票", Action: ActionVote})
+		prompts = append(prompts, prompt)
+	}
+	return prompts
+}
+
+// voteSummary lists who voted for whom and the count per candidate.
+func (g *Game) voteSummary(counts map[string]int) string {
+	var sb strings.Builder
+	sb.WriteString("投票結果:")
+	for _, id := range g.Ballot.Candidates {
+		if counts[id] == 0 {
+			continue
+		}
+		var voters []string
+		for _, voter := range g.Voters() {
+			if g.Ballot.Votes[voter.UserID] == id {
+				voters = append(voters, voter.UserID)
+			}
+		}
+		p, _ := g.Player(id)
+		sb.WriteString("\n")
+		sb.WriteString(p.Label())
+		sb.WriteString(" ")
+		sb.WriteString(strconv.Itoa(counts[id]))
+		sb.WriteString("票 (")
+		sb.WriteString(g.names(voters))
+		sb.WriteString(")")
+	}
+	var abstained []string
+	for _, voter := range g.Voters() {
+		if g.Ballot.Votes[voter.UserID] == "" {
+			abstained = append(abstained, voter.UserID)
+		}
+	}
+	if len(abstained) > 0 {
+		sb.WriteString("\n棄票: ")
+		sb.WriteString(g.names(abstained))
+	}
+	return sb.String()
+}
+
+// names joins the names of the given players.
+func (g *Game) names(userIDs []string) string {
+	names := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if p, ok := g.Player(id); ok {
+			names = append(names, p.Label())
+		}
+	}
+	return strings.Join(names, "、")
+}
+
+// isVoter reports whether userID may vote in the open ballot.
+func (g *Game) isVoter(userID string) bool {
+	for _, p := range g.Voters() {
+		if p.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// isCandidate reports whether userID can be voted for in the open ballot.
+func (g *Game) isCandidate(userID string) bool {
+	return slices.Contains(g.Ballot.Candidates, userID)
+}
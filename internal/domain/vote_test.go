@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVotingGame starts a game of one wolf and four villagers and opens the first day vote.
+func newVotingGame(t *testing.T, rules Rules) *Game {
+	t.Helper()
+	g := NewGame("owner", []Participant{
+		{UserID: "w", Name: "W", Identity: Werewolf},
+		{UserID: "a", Name: "A", Identity: Villager},
+		{UserID: "b", Name: "B", Identity: Villager},
+		{UserID: "c", Name: "C", Identity: Villager},
+		{UserID: "d", Name: "D", Identity: Villager},
+	}, rules)
+	act(t, g, "w", ActionSkip, "")
+	act(t, g, "owner", ActionStartVote, "")
+	require.Equal(t, PhaseVote, g.Phase)
+	return g
+}
+
+func TestGame_VoteMajorityExiles(t *testing.T) {
+	g := newVotingGame(t, DefaultRules())
+	assert := assert.New(t)
+
+	prompts := g.Prompts()
+	require.Len(t, prompts, 5, "Every living player gets a ballot")
+	for _, p := range prompts {
+		for _, o := range p.Options {
+			assert.NotEqual(p.UserID, o.Target, "Players cannot vote for themselves")
+		}
+		assert.Equal(Option{Label: "棄票", Action: ActionVote}, p.Options[len(p.Options)-1])
+	}
+
+	act(t, g, "w", ActionVote, "a")
+	_, err := g.Act("w", ActionVote, "b")
+	assert.ErrorIs(err, ErrAlreadyVoted)
+	_, err = g.Act("b", ActionVote, "b")
+	assert.ErrorIs(err, ErrInvalidTarget)
+	_, err = g.Act("b", ActionWolfKill, "w")
+	assert.ErrorIs(err, ErrNotYourTurn)
+
+	act(t, g, "b", ActionVote, "a")
+	act(t, g, "c", ActionVote, "a")
+	act(t, g, "d", ActionVote, "w")
+	notices := act(t, g, "a", ActionVote, "")
+
+	require.Len(t, notices, 3)
+	assert.Equal("投票結果:\nW 1票 (D)\nA 3票 (W、B、C)\n棄票: A", notices[0].Text)
+	assert.Equal("A 被放逐", notices[1].Text)
+	assert.False(g.isAlive("a"))
+	assert.Equal(PhaseNight, g.Phase)
+	assert.Nil(g.Ballot)
+
+	require.Len(t, g.VoteHistory, 1)
+	assert.Equal("a", g.VoteHistory[0].Exiled)
+	assert.Equal(1, g.VoteHistory[0].Day)
+	assert.Len(g.VoteHistory[0].Votes, 5)
+}
+
+func TestGame_VoteTieRevote(t *testing.T) {
+	g := newVotingGame(t, Rules{TieRule: TieRevote})
+	assert := assert.New(t)
+
+	act(t, g, "w", ActionVote, "a")
+	act(t, g, "a", ActionVote, "w")
+	act(t, g, "b", ActionVote, "w")
+	act(t, g, "c", ActionVote, "a")
+	notices := act(t, g, "d", ActionVote, "")
+
+	assert.Equal("平票，請對 W、A 重新投票", notices[len(notices)-1].Text)
+	assert.Equal(PhaseVote, g.Phase)
+	assert.True(g.Ballot.Runoff)
+	assert.Equal([]string{"w", "a"}, g.Ballot.Candidates)
+
+	// Tied players do not vote in the re-vote.
+	assert.Len(g.Prompts(), 3)
+	_, err := g.Act("w", ActionVote, "a")
+	assert.ErrorIs(err, ErrNotYourTurn)
+	_, err = g.Act("b", ActionVote, "c")
+	assert.ErrorIs(err, ErrInvalidTarget, "Only tied players can be voted for")
+
+	act(t, g, "b", ActionVote, "w")
+	act(t, g, "c", ActionVote, "w")
+	act(t, g, "d", ActionVote, "a")
+
+	assert.False(g.isAlive("w"))
+	require.Len(t, g.VoteHistory, 2)
+	assert.Equal([]string{"w", "a"}, g.VoteHistory[0].Tied)
+	assert.True(g.VoteHistory[1].Runoff)
+	assert.Equal("w", g.VoteHistory[1].Exiled)
+}
+
+func TestGame_VoteSecondTieExilesNobody(t *testing.T) {
+	g := newVotingGame(t, Rules{TieRule: TieRevote})
+
+	act(t, g, "w", ActionVote, "a")
+	act(t, g, "a", ActionVote, "w")
+	act(t, g, "b", ActionVote, "")
+	act(t, g, "c", ActionVote, "")
+	act(t, g, "d", ActionVote, "")
+
+	act(t, g, "b", ActionVote, "w")
+	act(t, g, "c", ActionVote, "a")
+	notices := act(t, g, "d", ActionVote, "")
+
+	assert.Equal(t, "本輪無人被放逐，天黑請閉眼", notices[len(notices)-1].Text)
+	assert.Equal(t, PhaseNight, g.Phase)
+	assert.Len(t, g.AlivePlayers(), 5)
+}
+
+func TestGame_VoteTieNoExile(t *testing.T) {
+	g := newVotingGame(t, Rules{TieRule: TieNoExile})
+
+	act(t, g, "w", ActionVote, "a")
+	act(t, g, "a", ActionVote, "w")
+	act(t, g, "b", ActionVote, "")
+	act(t, g, "c", ActionVote, "")
+	act(t, g, "d", ActionVote, "")
+
+	assert.Equal(t, PhaseNight, g.Phase)
+	assert.Len(t, g.AlivePlayers(), 5)
+	require.Len(t, g.VoteHistory, 1)
+	assert.Empty(t, g.VoteHistory[0].Exiled)
+}
+
+func TestGame_VoteAllAbstain(t *testing.T) {
+	g := newVotingGame(t, DefaultRules())
+
+	for _, p := range g.Voters() {
+		act(t, g, p.UserID, ActionVote, "")
+	}
+
+	assert.Equal(t, PhaseNight, g.Phase)
+	assert.Len(t, g.AlivePlayers(), 5)
+}
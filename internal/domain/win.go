@@ -0,0 +1,98 @@
+package domain
+
+// WinRule decides when the wolves win.
+type WinRule int
+
+// Constants for the win rules.
+const (
+	WinSideKill WinRule = iota + 1 // 屠邊: wolves win by killing every god or every villager.
+	WinAllKill                     // 屠城: wolves win by killing every good player.
+)
+
+// String returns the string representation of a WinRule.
+func (w WinRule) String() string {
+	switch w {
+	case WinSideKill:
+		return "屠邊"
+	case WinAllKill:
+		return "屠城"
+	default:
+		return "unknown"
+	}
+}
+
+// Winner is the side that won a game.
+type Winner int
+
+// Constants for the winners.
+const (
+	WinnerNone       Winner = iota // The game goes on.
+	WinnerWolves                   // The wolf team won.
+	WinnerVillagers                // The good team won.
+	WinnerThirdParty               // Cupid and lovers from opposite sides won.
+)
+
+// String returns the string representation of a Winner.
+func (w Winner) String() string {
+	switch w {
+	case WinnerWolves:
+		return "狼人陣營"
+	case WinnerVillagers:
+		return "好人陣營"
+	case WinnerThirdParty:
+		return "第三方陣營"
+	default:
+		return "無"
+	}
+}
+
+// CheckWinner decides whether a side has won among the players under the given rule.
+// Players count for the team they currently play for, so linked lovers and a converted Wild Child switch sides.
+// The third party wins once it is the only team left alive; while it is alive no other side can win.
+// Otherwise good players win once every wolf is dead, which is checked before the wolves' condition.
+// Under WinSideKill a side that was never dealt (e.g. no gods on the board) does not count as killed.
+func CheckWinner(players []Player, rule WinRule) Winner {
+	var wolves, gods, villagers, third, totalGods, totalVillagers int
+	for _, p := range players {
+		switch p.camp() {
+		case FactionWolf:
+			if p.Alive {
+				wolves++
+			}
+		case FactionGod:
+			totalGods++
+			if p.Alive {
+				gods++
+			}
+		case FactionVillager:
+			totalVillagers++
+			if p.Alive {
+				villagers++
+			}
+		case FactionThirdParty:
+			if p.Alive {
+				third++
+			}
+		}
+	}
+
+	if third > 0 {
+		if wolves+gods+villagers == 0 {
+			return WinnerThirdParty
+		}
+		return WinnerNone
+	}
+	if wolves == 0 {
+		return WinnerVillagers
+	}
+	if rule == WinAllKill {
+		if gods+villagers == 0 {
+			return WinnerWolves
+		}
+		return WinnerNone
+	}
+	if (totalGods > 0 && gods == 0) || (totalVillagers > 0 && villagers == 0) {
+		return WinnerWolves
+	}
+	return WinnerNone
+}
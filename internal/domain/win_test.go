@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckWinner(t *testing.T) {
+	// players builds a board of one wolf, one god and two villagers with the given deaths.
+	players := func(dead ...Identity) []Player {
+		ps := []Player{
+			{Participant: Participant{Identity: Werewolf}, Alive: true},
+			{Participant: Participant{Identity: Seer}, Alive: true},
+			{Participant: Participant{Identity: Villager}, Alive: true},
+			{Participant: Participant{Identity: Villager}, Alive: true},
+		}
+		for _, d := range dead {
+			for i := range ps {
+				if ps[i].Identity == d && ps[i].Alive {
+					ps[i].Alive = false
+					break
+				}
+			}
+		}
+		return ps
+	}
+
+	tests := []struct {
+		name    string
+		players []Player
+		rule    WinRule
+		want    Winner
+	}{
+		{"nobody dead", players(), WinSideKill, WinnerNone},
+		{"wolves dead", players(Werewolf), WinSideKill, WinnerVillagers},
+		{"gods dead side-kill", players(Seer), WinSideKill, WinnerWolves},
+		{"gods dead all-kill", players(Seer), WinAllKill, WinnerNone},
+		{"one villager dead side-kill", players(Villager), WinSideKill, WinnerNone},
+		{"villagers dead side-kill", players(Villager, Villager), WinSideKill, WinnerWolves},
+		{"villagers dead all-kill", players(Villager, Villager), WinAllKill, WinnerNone},
+		{"good dead all-kill", players(Seer, Villager, Villager), WinAllKill, WinnerWolves},
+		{"everyone dead", players(Werewolf, Seer, Villager, Villager), WinAllKill, WinnerVillagers},
+		{
+			"no gods dealt side-kill",
+			[]Player{
+				{Participant: Participant{Identity: Werewolf}, Alive: true},
+				{Participant: Participant{Identity: Villager}, Alive: true},
+			},
+			WinSideKill, WinnerNone,
+		},
+		{
+			"third party alone",
+			[]Player{
+				{Participant: Participant{Identity: Werewolf}, Alive: true, Faction: FactionThirdParty},
+				{Participant: Participant{Identity: Villager}, Alive: true, Faction: FactionThirdParty},
+				{Participant: Participant{Identity: Cupid}, Alive: false, Faction: FactionThirdParty},
+				{Participant: Participant{Identity: Werewolf}, Alive: false},
+				{Participant: Participant{Identity: Seer}, Alive: false},
+			},
+			WinSideKill, WinnerThirdParty,
+		},
+		{
+			"third party blocks good win",
+			[]Player{
+				{Participant: Participant{Identity: Werewolf}, Alive: true, Faction: FactionThirdParty},
+				{Participant: Participant{Identity: Villager}, Alive: true, Faction: FactionThirdParty},
+				{Participant: Participant{Identity: Werewolf}, Alive: false},
+				{Participant: Participant{Identity: Seer}, Alive: true},
+			},
+			WinSideKill, WinnerNone,
+		},
+		{
+			"converted wild child keeps wolves alive",
+			[]Player{
+				{Participant: Participant{Identity: WildChild}, Alive: true, Faction: FactionWolf},
+				{Participant: Participant{Identity: Werewolf}, Alive: false},
+				{Participant: Participant{Identity: Seer}, Alive: true},
+				{Participant: Participant{Identity: Villager}, Alive: true},
+			},
+			WinSideKill, WinnerNone,
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CheckWinner(tt.players, tt.rule), tt.name)
+	}
+}
+
+func TestGame_EndsWhenWolvesExiled(t *testing.T) {
+	g := newVotingGame(t, DefaultRules())
+	voteOut(t, g, "w")
+
+	assert.Equal(t, PhaseEnded, g.Phase)
+	assert.Equal(t, WinnerVillagers, g.Winner)
+	assert.Empty(t, g.Prompts(), "Ended game should not prompt anyone")
+	_, err := g.Act("owner", ActionStartVote, "")
+	assert.ErrorIs(t, err, ErrGameEnded)
+}
+
+func TestGame_EndAnnouncement(t *testing.T) {
+	g := NewGame("owner", []Participant{
+		{UserID: "w", Name: "W", Identity: Werewolf},
+		{UserID: "s", Name: "S", Identity: Seer},
+		{UserID: "v", Name: "V", Identity: Villager},
+		{UserID: "u", Name: "U", Identity: Villager},
+	}, Rules{TieRule: TieRevote, WinRule: WinSideKill})
+
+	act(t, g, "w", ActionWolfKill, "s")
+	notices := act(t, g, "s", ActionSkip, "")
+
+	assert.Equal(t, PhaseEnded, g.Phase)
+	assert.Equal(t, WinnerWolves, g.Winner)
+	assert.Equal(t, "遊戲結束，狼人陣營獲勝\nW: 狼人\nS: 預言家 (死亡)\nV: 平民\nU: 平民", notices[len(notices)-1].Text)
+}
+
+func TestGame_HunterShotDecidesWinner(t *testing.T) {
+	g := NewGame("owner", []Participant{
+		{UserID: "w", Name: "W", Identity: Werewolf},
+		{UserID: "h", Name: "H", Identity: Hunter},
+		{UserID: "s", Name: "S", Identity: Seer},
+		{UserID: "v", Name: "V", Identity: Villager},
+	}, Rules{TieRule: TieRevote, WinRule: WinAllKill})
+
+	act(t, g, "w", ActionWolfKill, "h")
+	act(t, g, "s", ActionSkip, "")
+	assert.Equal(t, PhaseDay, g.Phase, "Game waits for the hunter")
+
+	act(t, g, "h", ActionHunterShoot, "w")
+	assert.Equal(t, PhaseEnded, g.Phase)
+	assert.Equal(t, WinnerVillagers, g.Winner)
+}
+
+func TestWinRule_String(t *testing.T) {
+	assert.Equal(t, "屠邊", WinSideKill.String())
+	assert.Equal(t, "屠城", WinAllKill.String())
+	assert.Equal(t, "unknown", WinRule(99).String())
+}
@@ -0,0 +1,58 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"werewolve-helper/internal/adapter/lineauth"
+)
+
+// IDTokenVerifier verifies the LIFF ID token sent with API requests.
+type IDTokenVerifier interface {
+	Verify(ctx context.Context, idToken string) (lineauth.Claims, error)
+}
+
+// errorResponse is the body of a failed API request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// authenticated wraps an API handler with verification of the "Authorization: Bearer <ID token>" header.
+// The handler receives the claims of the verified LINE user.
+func authenticated(verifier IDTokenVerifier, next func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || idToken == "" {
+			writeError(w, http.StatusUnauthorized, "missing ID token")
+			return
+		}
+		claims, err := verifier.Verify(r.Context(), idToken)
+		if errors.Is(err, lineauth.ErrInvalidIDToken) {
+			writeError(w, http.StatusUnauthorized, "invalid ID token")
+			return
+		}
+		if err != nil {
+			log.Printf("verify ID token error: %v", err)
+			writeError(w, http.StatusBadGateway, "cannot verify ID token")
+			return
+		}
+		next(w, r, claims)
+	}
+}
+
+// writeJSON writes v as the JSON body of a response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response error: %v", err)
+	}
+}
+
+// writeError writes an error response with the given status.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
@@ -0,0 +1,49 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"werewolve-helper/internal/domain"
+)
+
+// balanceResponse is the body returned by GET /api/balance.
+type balanceResponse struct {
+	Score     int    `json:"score"`     // Positive favours the good side, negative the wolves.
+	GoodPower int    `json:"goodPower"` // Summed weight of the good side.
+	WolfPower int    `json:"wolfPower"` // Summed weight of the wolves.
+	Advantage string `json:"advantage"` // "平衡", "好人優勢" or "狼人優勢".
+	Summary   string `json:"summary"`   // Text to show the owner, e.g. "平衡 (+2)".
+}
+
+// RegisterBalanceAPI registers GET /api/balance, which scores a board given as role counts keyed by query key,
+// e.g. /api/balance?b0=3&g1=1&g2=1&g3=1&g0=3. Scoring reveals nothing about any round, so no ID token is needed.
+func RegisterBalanceAPI() {
+	http.HandleFunc("GET /api/balance", handleBalanceAPI)
+}
+
+// handleBalanceAPI handles GET /api/balance.
+func handleBalanceAPI(w http.ResponseWriter, r *http.Request) {
+	counts := make(map[domain.Identity]int)
+	for key, values := range r.URL.Query() {
+		role, ok := domain.RoleByQueryKey(key)
+		if !ok {
+			writeError(w, http.StatusBadRequest, errUnknownRole.Error()+" "+strconv.Quote(key))
+			return
+		}
+		n, err := strconv.Atoi(values[0])
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid count of "+key)
+			return
+		}
+		counts[role.Identity] = n
+	}
+
+	b := domain.ScoreBoard(counts)
+	writeJSON(w, http.StatusOK, balanceResponse{
+		Score:     b.Score,
+		GoodPower: b.GoodPower,
+		WolfPower: b.WolfPower,
+		Advantage: b.Advantage.String(),
+		Summary:   b.String(),
+	})
+}
@@ -0,0 +1,285 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"werewolve-helper/internal/adapter/lineauth"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// roomResponse is a host's view of a round on the room dashboard.
+type roomResponse struct {
+	InviteNo     string           `json:"inviteNo"`
+	Summary      string           `json:"summary"` // Faction counts, e.g. "3狼 3神 3民".
+	Joined       int              `json:"joined"`
+	Total        int              `json:"total"`
+	GameStarted  bool             `json:"gameStarted"`
+	ExpiredAt    time.Time        `json:"expiredAt"`
+	Seats        []seatResponse   `json:"seats"`
+	OwnerPlays   bool             `json:"ownerPlays"`   // Whether the owner plays instead of moderating.
+	CoHosts      []coHostResponse `json:"coHosts"`      // Users helping the owner moderate.
+	RolesVisible bool             `json:"rolesVisible"` // Whether the seats show the identities.
+	CanModerate  bool             `json:"canModerate"`  // Whether the viewer may start the game.
+	CanManage    bool             `json:"canManage"`    // Whether the viewer may kick, seat and close.
+	CanGrant     bool             `json:"canGrant"`     // Whether the viewer may revoke co-hosts.
+}
+
+// coHostResponse is a co-host of a round.
+type coHostResponse struct {
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+}
+
+// seatMapResponse is the seat map of a round as players see it, without identities.
+type seatMapResponse struct {
+	InviteNo    string         `json:"inviteNo"`
+	GameStarted bool           `json:"gameStarted"` // Seats are locked once the game started.
+	Owner       bool           `json:"owner"`       // Whether the viewer may move anyone and randomize the seats.
+	UserID      string         `json:"userId"`      // Viewer's user ID, to find their own seat.
+	Seats       []seatResponse `json:"seats"`
+}
+
+// seatResponse is a seat of the seat grid, joined or still free.
+type seatResponse struct {
+	Seat       int    `json:"seat"`              // Seat number, from 1.
+	Role       string `json:"role,omitempty"`    // Identity of the player sitting there; on the dashboard only.
+	Faction    string `json:"faction,omitempty"` // Faction of the player sitting there; on the dashboard only.
+	Joined     bool   `json:"joined"`
+	UserID     string `json:"userId,omitempty"`
+	Name       string `json:"name,omitempty"`
+	PictureURL string `json:"pictureUrl,omitempty"`
+}
+
+// RegisterRoomAPI registers the REST endpoints behind the room dashboard and the seat picker.
+// The owner and the co-hosts may use the dashboard, each within their permissions:
+//
+//	GET    /api/rooms/{inviteNo}                       shows the seat grid
+//	POST   /api/rooms/{inviteNo}/reshuffle             deals the identities again
+//	POST   /api/rooms/{inviteNo}/start                 starts the game engine
+//	DELETE /api/rooms/{inviteNo}/participants/{userId} kicks a participant
+//	POST   /api/rooms/{inviteNo}/seats/randomize       shuffles the seats
+//	DELETE /api/rooms/{inviteNo}/cohosts/{userId}      revokes a co-host
+//	DELETE /api/rooms/{inviteNo}                       closes the room
+//
+// Participants may also use the seat picker:
+//
+//	GET    /api/rooms/{inviteNo}/seats                 shows the seat map, without identities
+//	PUT    /api/rooms/{inviteNo}/seats/{seat}          moves the viewer, or for the owner the given userId, to the seat
+//
+// Every invite number in a path goes through the limiter, and a room the user has no part in is not found.
+func RegisterRoomAPI(verifier IDTokenVerifier, bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, limiter *JoinLimiter) {
+	http.Handle("/api/rooms/", newRoomAPIHandler(verifier, bot, rm, limiter))
+}
+
+// newRoomAPIHandler serves the room dashboard API.
+func newRoomAPIHandler(verifier IDTokenVerifier, bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, limiter *JoinLimiter) http.Handler {
+	mux := http.NewServeMux()
+	guarded := func(next func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims)) http.HandlerFunc {
+		return authenticated(verifier, limitRoomAccess(limiter, rm, next))
+	}
+
+	mux.HandleFunc("GET /api/rooms/{inviteNo}", guarded(func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		writeRoom(w, rm, r.PathValue("inviteNo"), claims.UserID)
+	}))
+
+	mux.HandleFunc("POST /api/rooms/{inviteNo}/reshuffle", guarded(func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		inviteNo := r.PathValue("inviteNo")
+		if err := rm.Again(inviteNo, claims.UserID); err != nil {
+			writeError(w, roomErrorStatus(err), err.Error())
+			return
+		}
+		writeRoom(w, rm, inviteNo, claims.UserID)
+	}))
+
+	mux.HandleFunc("POST /api/rooms/{inviteNo}/start", guarded(func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		inviteNo := r.PathValue("inviteNo")
+		summary, err := rm.FindByInviteNo(inviteNo)
+		var update usecase.GameUpdate
+		if err == nil {
+			update, err = rm.StartGame(inviteNo, claims.UserID)
+		}
+		if err != nil {
+			writeError(w, roomErrorStatus(err), err.Error())
+			return
+		}
+		if err := deliverGameUpdate(bot, summary.OwnerID, update); err != nil {
+			log.Printf("deliver game update of %s error: %v", inviteNo, err)
+		}
+		writeRoom(w, rm, inviteNo, claims.UserID)
+	}))
+
+	mux.HandleFunc("DELETE /api/rooms/{inviteNo}/participants/{userId}", guarded(func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		inviteNo := r.PathValue("inviteNo")
+		if err := rm.Kick(inviteNo, claims.UserID, r.PathValue("userId")); err != nil {
+			writeError(w, roomErrorStatus(err), err.Error())
+			return
+		}
+		writeRoom(w, rm, inviteNo, claims.UserID)
+	}))
+
+	mux.HandleFunc("GET /api/rooms/{inviteNo}/seats", guarded(func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		writeSeatMap(w, rm, r.PathValue("inviteNo"), claims.UserID)
+	}))
+
+	mux.HandleFunc("PUT /api/rooms/{inviteNo}/seats/{seat}", guarded(func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		seat, err := strconv.Atoi(r.PathValue("seat"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid seat")
+			return
+		}
+		// The body is optional: without a userId the viewer moves themselves.
+		var req struct {
+			UserID string `json:"userId"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body")
+				return
+			}
+		}
+		if req.UserID == "" {
+			req.UserID = claims.UserID
+		}
+		inviteNo := r.PathValue("inviteNo")
+		if err := rm.MoveSeat(inviteNo, claims.UserID, req.UserID, seat); err != nil {
+			writeError(w, roomErrorStatus(err), err.Error())
+			return
+		}
+		writeSeatMap(w, rm, inviteNo, claims.UserID)
+	}))
+
+	mux.HandleFunc("POST /api/rooms/{inviteNo}/seats/randomize", guarded(func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		inviteNo := r.PathValue("inviteNo")
+		if err := rm.RandomizeSeats(inviteNo, claims.UserID); err != nil {
+			writeError(w, roomErrorStatus(err), err.Error())
+			return
+		}
+		writeRoom(w, rm, inviteNo, claims.UserID)
+	}))
+
+	mux.HandleFunc("DELETE /api/rooms/{inviteNo}/cohosts/{userId}", guarded(func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		inviteNo, userID := r.PathValue("inviteNo"), r.PathValue("userId")
+		if err := rm.RevokeCoHost(inviteNo, claims.UserID, userID); err != nil {
+			writeError(w, roomErrorStatus(err), err.Error())
+			return
+		}
+		pushText(bot, userID, "你已不再是房間 "+inviteNo+" 的主持人")
+		writeRoom(w, rm, inviteNo, claims.UserID)
+	}))
+
+	mux.HandleFunc("DELETE /api/rooms/{inviteNo}", guarded(func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		if err := rm.Close(r.PathValue("inviteNo"), claims.UserID); err != nil {
+			writeError(w, roomErrorStatus(err), err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	return mux
+}
+
+// writeRoom writes the dashboard view of the round.
+func writeRoom(w http.ResponseWriter, rm *usecase.RoundManager, inviteNo, userID string) {
+	room, err := rm.Room(inviteNo, userID)
+	if err != nil {
+		writeError(w, roomErrorStatus(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newRoomResponse(room))
+}
+
+// writeSeatMap writes the seat map of the round as the viewer may see it.
+func writeSeatMap(w http.ResponseWriter, rm *usecase.RoundManager, inviteNo, userID string) {
+	view, err := rm.Seats(inviteNo, userID)
+	if err != nil {
+		writeError(w, roomErrorStatus(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, seatMapResponse{
+		InviteNo:    view.InviteNo,
+		GameStarted: view.GameStarted,
+		Owner:       view.Owner,
+		UserID:      userID,
+		Seats:       newSeatResponses(view.Seats, view.Participants, false),
+	})
+}
+
+// newRoomResponse converts a room view to its API representation, with a seat per identity.
+func newRoomResponse(room usecase.RoomView) roomResponse {
+	seats := newSeatResponses(len(room.Identities), room.Participants, room.RolesVisible)
+	coHosts := make([]coHostResponse, 0, len(room.CoHosts))
+	for _, c := range room.CoHosts {
+		coHosts = append(coHosts, coHostResponse{UserID: c.UserID, Name: c.Name})
+	}
+	return roomResponse{
+		InviteNo:     room.InviteNo,
+		Summary:      domain.CompositionSummary(room.Identities),
+		Joined:       len(room.Participants),
+		Total:        len(room.Identities),
+		GameStarted:  room.GameStarted,
+		ExpiredAt:    room.ExpiredAt,
+		Seats:        seats,
+		OwnerPlays:   room.OwnerPlays,
+		CoHosts:      coHosts,
+		RolesVisible: room.RolesVisible,
+		CanModerate:  room.CanModerate,
+		CanManage:    room.CanManage,
+		CanGrant:     room.CanGrant,
+	}
+}
+
+// newSeatResponses lays the participants out on the seats numbered 1 to n, the identities shown only if withRoles.
+// Participants without a seat, from rounds stored before seats existed, fill the free seats in order.
+func newSeatResponses(n int, participants []domain.Participant, withRoles bool) []seatResponse {
+	seats := make([]seatResponse, n)
+	for i := range seats {
+		seats[i].Seat = i + 1
+	}
+	var unseated []domain.Participant
+	for _, p := range participants {
+		if p.Seat < 1 || p.Seat > n || seats[p.Seat-1].Joined {
+			unseated = append(unseated, p)
+			continue
+		}
+		seats[p.Seat-1] = newSeatResponse(p.Seat, p, withRoles)
+	}
+	for i := range seats {
+		if len(unseated) > 0 && !seats[i].Joined {
+			seats[i] = newSeatResponse(i+1, unseated[0], withRoles)
+			unseated = unseated[1:]
+		}
+	}
+	return seats
+}
+
+// newSeatResponse describes the seat taken by the participant.
+func newSeatResponse(seat int, p domain.Participant, withRoles bool) seatResponse {
+	res := seatResponse{Seat: seat, Joined: true, UserID: p.UserID, Name: p.Name, PictureURL: p.PictureURL}
+	if withRoles {
+		res.Role = p.Identity.String()
+		res.Faction = p.Identity.Faction().String()
+	}
+	return res
+}
+
+// roomErrorStatus maps room errors to HTTP statuses.
+func roomErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrRoundNotFound), errors.Is(err, domain.ErrNotParticipant), errors.Is(err, domain.ErrNotCoHost):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrNotPermitted):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrGameStarted), errors.Is(err, domain.ErrRegistrationOpen), errors.Is(err, domain.ErrSeatTaken):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrSeatInvalid):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,198 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomAPI_OwnerOnly(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 2)
+	require.NoError(t, rm.Create(round))
+	require.Equal(t, usecase.JoinJoined, rm.Join("000001", "user1", "User One", "https://example.com/u1.png").Status)
+
+	_, bot := newFakeLineAPI(t)
+	handler := newRoomAPIHandler(fakeVerifier{"owner": {UserID: "owner1"}, "player": {UserID: "user1"}}, bot, rm, NewJoinLimiter(defaultJoinLimits, time.Now))
+	serve := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	assert := assert.New(t)
+
+	rec := serve(http.MethodGet, "/api/rooms/000001", "owner")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var res roomResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(1, res.Joined)
+	require.Len(t, res.Seats, 2)
+	assert.True(res.Seats[0].Joined)
+	assert.Equal("https://example.com/u1.png", res.Seats[0].PictureURL)
+	assert.False(res.Seats[1].Joined)
+
+	// Players, even of the same round, cannot manage it.
+	assert.Equal(http.StatusForbidden, serve(http.MethodGet, "/api/rooms/000001", "player").Code)
+	assert.Equal(http.StatusForbidden, serve(http.MethodDelete, "/api/rooms/000001/participants/user1", "player").Code)
+	assert.Equal(http.StatusForbidden, serve(http.MethodDelete, "/api/rooms/000001", "player").Code)
+	assert.Equal(http.StatusNotFound, serve(http.MethodGet, "/api/rooms/999999", "owner").Code)
+
+	assert.Equal(http.StatusConflict, serve(http.MethodPost, "/api/rooms/000001/start", "owner").Code, "The room is not full yet")
+	assert.Equal(http.StatusOK, serve(http.MethodDelete, "/api/rooms/000001/participants/user1", "owner").Code)
+	assert.Equal(http.StatusNoContent, serve(http.MethodDelete, "/api/rooms/000001", "owner").Code)
+	assert.False(rm.HasInviteNo("000001"), "The room should be closed")
+}
+
+func TestRoomAPI_CoHost(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 2)
+	require.NoError(t, rm.Create(round))
+	require.NoError(t, rm.GrantCoHost("000001", "owner1", "host1", "Host"))
+	require.NoError(t, rm.SetOwnerPlays("000001", "owner1", true))
+	require.Equal(t, usecase.JoinJoined, rm.Join("000001", "owner1", "Owner", "").Status)
+
+	_, bot := newFakeLineAPI(t)
+	handler := newRoomAPIHandler(fakeVerifier{"owner": {UserID: "owner1"}, "host": {UserID: "host1"}}, bot, rm, NewJoinLimiter(defaultJoinLimits, time.Now))
+	serve := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	room := func(token string) roomResponse {
+		rec := serve(http.MethodGet, "/api/rooms/000001", token)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var res roomResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res
+	}
+	assert := assert.New(t)
+
+	host := room("host")
+	assert.True(host.RolesVisible)
+	assert.Equal("平民", host.Seats[0].Role)
+	assert.Equal([]coHostResponse{{UserID: "host1", Name: "Host"}}, host.CoHosts)
+
+	owner := room("owner")
+	assert.True(owner.OwnerPlays)
+	assert.False(owner.RolesVisible)
+	assert.Empty(owner.Seats[0].Role, "A playing owner should not see the roles")
+
+	assert.Equal(http.StatusOK, serve(http.MethodPost, "/api/rooms/000001/reshuffle", "host").Code)
+	assert.Equal(http.StatusForbidden, serve(http.MethodPost, "/api/rooms/000001/seats/randomize", "host").Code)
+	assert.Equal(http.StatusForbidden, serve(http.MethodDelete, "/api/rooms/000001", "host").Code)
+	assert.Equal(http.StatusForbidden, serve(http.MethodDelete, "/api/rooms/000001/cohosts/host1", "host").Code)
+
+	assert.Equal(http.StatusOK, serve(http.MethodDelete, "/api/rooms/000001/cohosts/host1", "owner").Code)
+	assert.Equal(http.StatusNotFound, serve(http.MethodDelete, "/api/rooms/000001/cohosts/host1", "owner").Code)
+	assert.Equal(http.StatusNotFound, serve(http.MethodGet, "/api/rooms/000001", "host").Code, "A revoked co-host no longer learns the room exists")
+}
+
+func TestRoomAPI_Seats(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 3)
+	require.NoError(t, rm.Create(round))
+	for _, id := range []string{"user1", "user2"} {
+		require.Equal(t, usecase.JoinJoined, rm.Join("000001", id, id, "").Status)
+	}
+
+	_, bot := newFakeLineAPI(t)
+	handler := newRoomAPIHandler(fakeVerifier{
+		"owner": {UserID: "owner1"}, "player": {UserID: "user1"}, "stranger": {UserID: "stranger"},
+	}, bot, rm, NewJoinLimiter(defaultJoinLimits, time.Now))
+	serve := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	assert := assert.New(t)
+
+	rec := serve(http.MethodGet, "/api/rooms/000001/seats", "player", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var res seatMapResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.False(res.Owner)
+	require.Len(t, res.Seats, 3)
+	assert.Equal("user1", res.Seats[0].UserID)
+	assert.Empty(res.Seats[0].Role, "Players must not see identities")
+	assert.False(res.Seats[2].Joined)
+	assert.Equal(http.StatusNotFound, serve(http.MethodGet, "/api/rooms/000001/seats", "stranger", "").Code)
+
+	// Players pick a free seat for themselves only.
+	assert.Equal(http.StatusConflict, serve(http.MethodPut, "/api/rooms/000001/seats/2", "player", "").Code)
+	assert.Equal(http.StatusBadRequest, serve(http.MethodPut, "/api/rooms/000001/seats/9", "player", "").Code)
+	assert.Equal(http.StatusForbidden, serve(http.MethodPut, "/api/rooms/000001/seats/3", "player", `{"userId":"user2"}`).Code)
+	assert.Equal(http.StatusOK, serve(http.MethodPut, "/api/rooms/000001/seats/3", "player", "").Code)
+
+	// The owner moves anyone and sees the roles on the dashboard.
+	assert.Equal(http.StatusOK, serve(http.MethodPut, "/api/rooms/000001/seats/3", "owner", `{"userId":"user2"}`).Code)
+	rec = serve(http.MethodGet, "/api/rooms/000001", "owner", "")
+	var room roomResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &room))
+	assert.Equal("user1", room.Seats[1].UserID)
+	assert.Equal("user2", room.Seats[2].UserID)
+	assert.Equal("平民", room.Seats[2].Role)
+
+	assert.Equal(http.StatusForbidden, serve(http.MethodPost, "/api/rooms/000001/seats/randomize", "player", "").Code)
+	assert.Equal(http.StatusOK, serve(http.MethodPost, "/api/rooms/000001/seats/randomize", "owner", "").Code)
+}
+
+func TestRoomAPI_LimitsGuessing(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 3)
+	require.NoError(t, rm.Create(round))
+	_, bot := newFakeLineAPI(t)
+	limiter, _ := newTestLimiter(JoinLimits{Window: time.Minute, PerUser: 100, Global: 100, MaxFailures: 2, Lockout: 15 * time.Minute})
+	handler := newRoomAPIHandler(fakeVerifier{"owner": {UserID: "owner1"}, "stranger": {UserID: "stranger"}}, bot, rm, limiter)
+	serve := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	assert := assert.New(t)
+
+	// A stranger cannot tell a wrong number from a room they have no part in.
+	wrong := serve(http.MethodGet, "/api/rooms/123456/seats", "stranger")
+	right := serve(http.MethodGet, "/api/rooms/000001/seats", "stranger")
+	assert.Equal(http.StatusNotFound, wrong.Code)
+	assert.Equal(wrong.Code, right.Code)
+	assert.Equal(wrong.Body.String(), right.Body.String())
+	assert.Equal(http.StatusNotFound, serve(http.MethodDelete, "/api/rooms/000001", "stranger").Code)
+
+	// Wrong numbers count towards a lockout on every endpoint.
+	serve(http.MethodGet, "/api/rooms/123456", "stranger")
+	serve(http.MethodPost, "/api/rooms/654321/reshuffle", "stranger")
+	rec := serve(http.MethodGet, "/api/rooms/000001", "stranger")
+	assert.Equal(http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(rec.Header().Get("Retry-After"))
+	assert.Contains(rec.Body.String(), "輸入錯誤的房間號碼太多次")
+
+	// Hosts using their own room are never limited.
+	for range 5 {
+		assert.Equal(http.StatusOK, serve(http.MethodGet, "/api/rooms/000001", "owner").Code)
+	}
+}
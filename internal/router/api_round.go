@@ -0,0 +1,120 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"werewolve-helper/internal/adapter/lineauth"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// errUnknownRole is returned when a create round request names a role that is not registered.
+var errUnknownRole = errors.New("unknown role")
+
+// maxRequestBodySize bounds the body of API requests.
+const maxRequestBodySize = 64 << 10
+
+// createRoundRequest is the body of POST /api/rounds sent by the LIFF setting page.
+type createRoundRequest struct {
+	Roles   map[string]int `json:"roles"`   // {key: role query key, e.g. "b0", value: number of cards}
+	TieRule string         `json:"tieRule"` // "revote" (default) or "none".
+	WinRule string         `json:"winRule"` // "side" (default) or "all".
+
+	HiddenWolfRevealed bool `json:"hiddenWolfRevealed"` // Let the wolves know the Hidden Wolf.
+	GhostRiderHidden   bool `json:"ghostRiderHidden"`   // Keep the Ghost Rider unknown to the wolves.
+	OwnerPlays         bool `json:"ownerPlays"`         // Deal the owner a card too, hiding the roles from them.
+}
+
+// createRoundResponse is the body returned when a round has been created.
+type createRoundResponse struct {
+	InviteNo string         `json:"inviteNo"`
+	Warnings []domain.Issue `json:"warnings"` // Reasons the board may be unbalanced.
+}
+
+// boardErrorResponse is the body returned when the requested board is invalid.
+type boardErrorResponse struct {
+	Error  string         `json:"error"`
+	Issues []domain.Issue `json:"issues"`
+}
+
+// counts converts the requested role counts to identity counts.
+func (req createRoundRequest) counts() (map[domain.Identity]int, error) {
+	counts := make(map[domain.Identity]int, len(req.Roles))
+	for key, n := range req.Roles {
+		role, ok := domain.RoleByQueryKey(key)
+		if !ok {
+			return nil, fmt.Errorf("%w %q", errUnknownRole, key)
+		}
+		counts[role.Identity] = n
+	}
+	return counts, nil
+}
+
+// RegisterRoundAPI registers POST /api/rounds, which creates a round for the LINE user of the ID token.
+func RegisterRoundAPI(verifier IDTokenVerifier, bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, botBasicID, liffRoomID string) {
+	http.Handle("POST /api/rounds", newRoundAPIHandler(verifier, bot, rm, botBasicID, liffRoomID))
+}
+
+// newRoundAPIHandler handles POST /api/rounds. The invite number is returned and also pushed to the owner's chat,
+// along with the owner's role card if they play.
+func newRoundAPIHandler(verifier IDTokenVerifier, bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, botBasicID, liffRoomID string) http.Handler {
+	return authenticated(verifier, func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		var req createRoundRequest
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+
+		counts, err := req.counts()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		warnings, err := domain.ValidateBoard(counts)
+		var boardErr *domain.BoardError
+		if errors.As(err, &boardErr) {
+			writeJSON(w, http.StatusBadRequest, boardErrorResponse{Error: "invalid board", Issues: boardErr.Issues})
+			return
+		}
+
+		inviteNo, err := rm.NewInviteNo()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		round := newRoundFromCounts(claims.UserID, inviteNo, counts)
+		round.Rules = parseRules(req.TieRule, req.WinRule)
+		round.Rules.HiddenWolfRevealed = req.HiddenWolfRevealed
+		round.Rules.GhostRiderHidden = req.GhostRiderHidden
+		var owner domain.Participant
+		if req.OwnerPlays {
+			if owner, err = round.PlayAsOwner(claims.Name, claims.PictureURL); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if err := rm.Create(round); err != nil {
+			writeError(w, http.StatusConflict, "創建失敗，請重新嘗試")
+			return
+		}
+
+		messages := roundCreatedMessages(round, botBasicID, warnings)
+		if req.OwnerPlays {
+			messages = append(messages, roleCard(rm, round.InviteNo, owner, liffRoomID))
+		}
+		if err := pushMessage(bot, claims.UserID, messages...); err != nil {
+			log.Printf("push round %s error: %v", round.InviteNo, err)
+		}
+		if warnings == nil {
+			warnings = []domain.Issue{}
+		}
+		writeJSON(w, http.StatusCreated, createRoundResponse{InviteNo: round.InviteNo, Warnings: warnings})
+	})
+}
@@ -0,0 +1,185 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"werewolve-helper/internal/adapter/lineauth"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVerifier accepts the ID tokens it knows.
+type fakeVerifier map[string]lineauth.Claims // {key: ID token, value: Claims}
+
+func (v fakeVerifier) Verify(_ context.Context, idToken string) (lineauth.Claims, error) {
+	claims, ok := v[idToken]
+	if !ok {
+		return lineauth.Claims{}, lineauth.ErrInvalidIDToken
+	}
+	return claims, nil
+}
+
+// fakeLineAPI records the requests sent to the LINE Messaging API.
+type fakeLineAPI struct {
+	mu       sync.Mutex
+	requests map[string][]string // {key: request path, value: request bodies}
+}
+
+// newFakeLineAPI starts a fake LINE Messaging API and returns a bot client talking to it.
+// Profiles are answered with the display name "Player {userId}"; every other request with an empty object.
+func newFakeLineAPI(t *testing.T) (*fakeLineAPI, *messaging_api.MessagingApiAPI) {
+	t.Helper()
+	api := &fakeLineAPI{requests: make(map[string][]string)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		api.mu.Lock()
+		api.requests[r.URL.Path] = append(api.requests[r.URL.Path], string(body))
+		api.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if userID, ok := strings.CutPrefix(r.URL.Path, "/v2/bot/profile/"); ok {
+			_ = json.NewEncoder(w).Encode(messaging_api.UserProfileResponse{UserId: userID, DisplayName: "Player " + userID})
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	t.Cleanup(srv.Close)
+
+	bot, err := messaging_api.NewMessagingApiAPI("token", messaging_api.WithEndpoint(srv.URL))
+	require.NoError(t, err)
+	return api, bot
+}
+
+// sent returns the bodies of the requests sent to path.
+func (api *fakeLineAPI) sent(path string) []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return append([]string(nil), api.requests[path]...)
+}
+
+func TestRoundAPI_CreatesRound(t *testing.T) {
+	assert := assert.New(t)
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	api, bot := newFakeLineAPI(t)
+	handler := newRoundAPIHandler(fakeVerifier{"good": {UserID: "owner1"}}, bot, rm, "@bot", "")
+
+	body := `{"roles": {"b0": 1, "g1": 1, "g2": 1, "g0": 3}, "tieRule": "none", "winRule": "all", "ghostRiderHidden": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/rounds", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer good")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var res createRoundResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Len(res.InviteNo, 6)
+	assert.True(rm.HasInviteNo(res.InviteNo))
+	assert.Equal([]domain.Issue{{Code: domain.IssueWolvesTooFew, Message: "狼人比例偏低 (1狼 / 6人)"}}, res.Warnings)
+
+	identities, rules, err := rm.Composition("owner1")
+	require.NoError(t, err)
+	assert.Equal("1狼 2神 3民", domain.CompositionSummary(identities))
+	assert.Equal(domain.Rules{TieRule: domain.TieNoExile, WinRule: domain.WinAllKill, GhostRiderHidden: true}, rules)
+
+	pushes := api.sent("/v2/bot/message/push")
+	require.Len(t, pushes, 1, "The invite number is pushed to the owner")
+	assert.Contains(pushes[0], `"to":"owner1"`)
+	assert.Contains(pushes[0], res.InviteNo)
+	assert.Contains(pushes[0], "狼人比例偏低")
+}
+
+func TestRoundAPI_OwnerPlays(t *testing.T) {
+	assert := assert.New(t)
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	api, bot := newFakeLineAPI(t)
+	handler := newRoundAPIHandler(fakeVerifier{"good": {UserID: "owner1", Name: "Owner"}}, bot, rm, "@bot", "")
+
+	body := `{"roles": {"b0": 1, "g1": 1, "g2": 1, "g0": 3}, "ownerPlays": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/rounds", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer good")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	_, info, err := rm.Look("owner1")
+	require.NoError(t, err)
+	assert.Contains(info, "目前參與人數: 1/6")
+	assert.Contains(info, "1號 Owner:已加入", "The owner joins on creation, their role hidden")
+
+	pushes := api.sent("/v2/bot/message/push")
+	require.Len(t, pushes, 1)
+	assert.Contains(pushes[0], "1號", "The owner's card is pushed with the invite number")
+}
+
+func TestRoundAPI_ListsBoardIssues(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	_, bot := newFakeLineAPI(t)
+	handler := newRoundAPIHandler(fakeVerifier{"good": {UserID: "owner1"}}, bot, rm, "@bot", "")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/rounds", strings.NewReader(`{"roles": {"g2": 2, "g0": 1}}`))
+	req.Header.Set("Authorization", "Bearer good")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	var res boardErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	var codes []domain.IssueCode
+	for _, i := range res.Issues {
+		codes = append(codes, i.Code)
+	}
+	assert.Equal(t, []domain.IssueCode{domain.IssueUniqueRole, domain.IssueTooFewPlayers, domain.IssueNoWolves}, codes)
+}
+
+func TestRoundAPI_Rejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		body   string
+		status int
+	}{
+		{"missing token", "", `{"roles": {"b0": 1}}`, http.StatusUnauthorized},
+		{"forged token", "forged", `{"roles": {"b0": 1}}`, http.StatusUnauthorized},
+		{"invalid JSON", "good", `{"roles":`, http.StatusBadRequest},
+		{"unknown field", "good", `{"roles": {"b0": 1}, "owner": "someone"}`, http.StatusBadRequest},
+		{"unknown role", "good", `{"roles": {"x9": 1}}`, http.StatusBadRequest},
+		{"negative count", "good", `{"roles": {"b0": 1, "g0": -3}}`, http.StatusBadRequest},
+		{"too many cards", "good", `{"roles": {"b0": 1, "g0": 500}}`, http.StatusBadRequest},
+		{"no roles", "good", `{"roles": {}}`, http.StatusBadRequest},
+		{"two witches", "good", `{"roles": {"b0": 2, "g2": 2, "g0": 2}}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+			require.NoError(t, err)
+			api, bot := newFakeLineAPI(t)
+			handler := newRoundAPIHandler(fakeVerifier{"good": {UserID: "owner1"}}, bot, rm, "@bot", "")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/rounds", strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			_, _, err = rm.Composition("owner1")
+			assert.ErrorIs(t, err, usecase.ErrRoundNotFound, "No round should be created")
+			assert.Empty(t, api.sent("/v2/bot/message/push"))
+		})
+	}
+}
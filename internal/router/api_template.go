@@ -0,0 +1,112 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	"werewolve-helper/internal/adapter/lineauth"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+)
+
+// templateResponse describes a saved board template.
+type templateResponse struct {
+	Name       string    `json:"name"`
+	Summary    string    `json:"summary"`    // Faction counts, e.g. "3狼 3神 3民".
+	Identities []string  `json:"identities"` // Role names in registry order.
+	TieRule    string    `json:"tieRule"`
+	WinRule    string    `json:"winRule"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// saveTemplateRequest is the body of POST /api/templates.
+type saveTemplateRequest struct {
+	Name string `json:"name"`
+}
+
+// roundCreatedResponse is the body returned when a round has been created.
+type roundCreatedResponse struct {
+	InviteNo string `json:"inviteNo"`
+}
+
+// RegisterTemplateAPI registers the REST endpoints managing the caller's board templates:
+//
+//	GET    /api/templates               lists the templates
+//	POST   /api/templates               saves the current round's board as {"name": ...}
+//	DELETE /api/templates/{name}        deletes a template
+//	POST   /api/templates/{name}/rounds creates a round from a template
+func RegisterTemplateAPI(verifier IDTokenVerifier, tm *usecase.TemplateManager) {
+	http.HandleFunc("GET /api/templates", authenticated(verifier, func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		templates, err := tm.List(claims.UserID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		res := make([]templateResponse, 0, len(templates))
+		for _, t := range templates {
+			res = append(res, newTemplateResponse(t))
+		}
+		writeJSON(w, http.StatusOK, res)
+	}))
+
+	http.HandleFunc("POST /api/templates", authenticated(verifier, func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		var req saveTemplateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		tmpl, err := tm.Save(claims.UserID, req.Name)
+		if err != nil {
+			writeError(w, templateErrorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, newTemplateResponse(tmpl))
+	}))
+
+	http.HandleFunc("DELETE /api/templates/{name}", authenticated(verifier, func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		if err := tm.Delete(claims.UserID, r.PathValue("name")); err != nil {
+			writeError(w, templateErrorStatus(err), err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	http.HandleFunc("POST /api/templates/{name}/rounds", authenticated(verifier, func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		round, err := tm.CreateRound(claims.UserID, r.PathValue("name"))
+		if err != nil {
+			writeError(w, templateErrorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, roundCreatedResponse{InviteNo: round.InviteNo})
+	}))
+}
+
+// newTemplateResponse converts a template to its API representation.
+func newTemplateResponse(t *domain.BoardTemplate) templateResponse {
+	names := make([]string, 0, len(t.Identities))
+	for _, iden := range t.Identities {
+		names = append(names, iden.String())
+	}
+	return templateResponse{
+		Name:       t.Name,
+		Summary:    t.Summary(),
+		Identities: names,
+		TieRule:    t.Rules.TieRule.String(),
+		WinRule:    t.Rules.WinRule.String(),
+		UpdatedAt:  t.UpdatedAt,
+	}
+}
+
+// templateErrorStatus maps template errors to HTTP statuses.
+func templateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrTemplateNotFound), errors.Is(err, usecase.ErrNoRoundToTemplate):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTemplateNameInvalid), errors.Is(err, domain.ErrTemplateEmpty):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrTooManyTemplates), errors.Is(err, usecase.ErrInviteNoDuplicate):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
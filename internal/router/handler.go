@@ -11,31 +11,45 @@ import (
 	"werewolve-helper/internal"
 	"werewolve-helper/internal/adapter/notify"
 	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
 
-	"github.com/line/line-bot-sdk-go/v8/linebot"
 	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
 	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
 )
 
-// {key: ownerID, value: Round}
-var rounds = make(map[string]*domain.Round)
-
 // Postback event key
 const (
-	EventCreate = "create"
-	EventLook   = "look"
-	EventAgain  = "again"
+	EventCreate   = "create"
+	EventLook     = "look"
+	EventAgain    = "again"
+	EventStart    = "start"
+	EventGame     = "game"
+	EventPreset   = "preset"
+	EventTemplate = "template"
+	EventJoin     = "join"
+	EventLeave    = "leave"
+	EventKick     = "kick"
+	EventReplace  = "replace"
+	EventGrant    = "grant"
+	EventRevoke   = "revoke"
 )
 
-func RegisterWebhook(config internal.BotConfig, bot *messaging_api.MessagingApiAPI) {
+func RegisterWebhook(config internal.BotConfig, bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, tm *usecase.TemplateManager, limiter *JoinLimiter) {
 	// Setup HTTP Server for receiving requests from LINE platform
-	http.HandleFunc("/callback", func(w http.ResponseWriter, req *http.Request) {
+	http.Handle("/callback", newWebhookHandler(config, bot, rm, tm, limiter))
+}
+
+// newWebhookHandler serves the webhook events signed with the channel secret.
+// Invite numbers typed in 1:1 chats or carried by postbacks go through the limiter first.
+func newWebhookHandler(config internal.BotConfig, bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, tm *usecase.TemplateManager, limiter *JoinLimiter) http.Handler {
+	handleUserText := limitJoins(limiter, handleText)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		// log.Println("/callback called...")
 
 		cb, err := webhook.ParseRequest(config.LineChannelSecret, req)
 		if err != nil {
 			log.Printf("Cannot parse request: %+v\n", err)
-			if errors.Is(err, linebot.ErrInvalidSignature) {
+			if errors.Is(err, webhook.ErrInvalidSignature) {
 				w.WriteHeader(400)
 			} else {
 				w.WriteHeader(500)
@@ -53,16 +67,20 @@ func RegisterWebhook(config internal.BotConfig, bot *messaging_api.MessagingApiA
 				case webhook.TextMessageContent:
 					switch source := e.Source.(type) {
 					case webhook.UserSource:
-						if err := handleText(bot, e.ReplyToken, &message, source); err != nil {
+						if err := handleUserText(bot, rm, tm, e.ReplyToken, &message, source, config); err != nil {
 							log.Println("Handle text event error: ", err)
 						}
+					case webhook.GroupSource, webhook.RoomSource:
+						if err := handleGroupText(bot, rm, e.ReplyToken, &message, source, config.LiffRoomID); err != nil {
+							log.Println("Handle group text event error: ", err)
+						}
 					default:
 						log.Printf("Unsupported source content: %T\n", e.Source)
 					}
 				case webhook.ImageMessageContent:
 					switch source := e.Source.(type) {
 					case webhook.UserSource:
-						if err := handleImage(bot, e.ReplyToken, &message, source); err != nil {
+						if err := handleImage(bot, rm, e.ReplyToken, &message, source, config.LineBotBasicID); err != nil {
 							log.Println("Handle image event error: ", err)
 						}
 					default:
@@ -72,11 +90,21 @@ func RegisterWebhook(config internal.BotConfig, bot *messaging_api.MessagingApiA
 					log.Printf("Unsupported message content: %T\n", e.Message)
 				}
 			case webhook.PostbackEvent:
+				if ok, err := limitPostback(bot, rm, limiter, e.ReplyToken, e.Postback, sourceUserID(e.Source)); !ok {
+					if err != nil {
+						log.Println("Reply limited postback error: ", err)
+					}
+					continue
+				}
 				switch source := e.Source.(type) {
 				case webhook.UserSource:
-					if err := handlePostbackEvent(bot, e.ReplyToken, e.Postback, source, config.LiffID); err != nil {
+					if err := handlePostbackEvent(bot, rm, tm, e.ReplyToken, e.Postback, source, config); err != nil {
 						log.Println("Handle postback event error: ", err)
 					}
+				case webhook.GroupSource, webhook.RoomSource:
+					if err := handleGroupPostback(bot, rm, e.ReplyToken, e.Postback, source, config.LiffRoomID); err != nil {
+						log.Println("Handle group postback event error: ", err)
+					}
 				default:
 					log.Printf("Unsupported source content: %T\n", e.Source)
 				}
@@ -98,6 +126,21 @@ func RegisterWebhook(config internal.BotConfig, bot *messaging_api.MessagingApiA
 				default:
 					log.Printf("Unsupported source content: %T\n", e.Source)
 				}
+			case webhook.JoinEvent:
+				if err := handleBotJoined(bot, e.ReplyToken); err != nil {
+					log.Println("Handle join event error: ", err)
+				}
+			case webhook.LeaveEvent:
+				if chatID, _, ok := chatSource(e.Source); ok {
+					rm.UnbindGroup(chatID)
+				}
+			case webhook.MemberJoinedEvent:
+				if err := handleMembersJoined(bot, rm, e.ReplyToken, e.Source); err != nil {
+					log.Println("Handle member joined event error: ", err)
+				}
+			case webhook.MemberLeftEvent:
+				// Players who leave the chat keep their seat; the owner can still reach them privately.
+				log.Printf("Members left %T\n", e.Source)
 			default:
 				log.Printf("Unsupported event: %T\n", event)
 			}
@@ -105,49 +148,41 @@ func RegisterWebhook(config internal.BotConfig, bot *messaging_api.MessagingApiA
 	})
 }
 
-func handleText(bot *messaging_api.MessagingApiAPI, replyToken string, message *webhook.TextMessageContent, source webhook.UserSource) error {
-	text := message.Text
-
-	if ownerID := findRoundByInviteNo(text); ownerID != "" {
-
-		if r, ok := rounds[source.UserId]; ok {
-
-			if r.IsExpired() {
-				delete(rounds, ownerID)
-				m1 := messaging_api.TextMessage{Text: "活動已結束"}
-				return reply(bot, replyToken, m1)
-			}
-
-			user, err := bot.GetProfile(source.UserId)
-			if err != nil {
-				return err
-			}
-
-			if ok, p := r.IsRegistrationDuplicate(user.UserId); ok {
-				m1 := messaging_api.TextMessage{Text: "已註冊，你的身分是 " + p.Identity.String()}
-				return reply(bot, replyToken, m1)
-			}
-
-			iden := r.Register(source.UserId, user.DisplayName, user.PictureUrl)
-			if iden == "" {
-				m1 := messaging_api.TextMessage{Text: "已額滿"}
-				return reply(bot, replyToken, m1)
-			}
-			var sb strings.Builder
-			sb.WriteString("你的身分是 ")
-			sb.WriteString(iden)
-			m1 := messaging_api.TextMessage{Text: sb.String()}
-			return reply(bot, replyToken, m1)
-		}
+func handleText(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, tm *usecase.TemplateManager, replyToken string, message *webhook.TextMessageContent, source webhook.UserSource, config internal.BotConfig) error {
+	text := strings.TrimSpace(message.Text)
+
+	switch cmd, args, _ := strings.Cut(text, " "); cmd {
+	case presetCommand:
+		return handlePresetCommand(bot, rm, replyToken, strings.TrimSpace(args), source, config.LineBotBasicID)
+	case templateCommand:
+		return handleTemplateCommand(bot, tm, replyToken, strings.TrimSpace(args), source, config.LineBotBasicID)
+	case leaveCommand:
+		return handleLeave(bot, rm, replyToken, strings.TrimSpace(args), source)
+	case kickCommand:
+		return handleMemberCommand(bot, rm, replyToken, EventKick, strings.TrimSpace(args), source)
+	case replaceCommand:
+		return handleMemberCommand(bot, rm, replyToken, EventReplace, strings.TrimSpace(args), source)
+	case coHostCommand:
+		return handleCoHostCommand(bot, rm, replyToken, strings.TrimSpace(args), source)
+	case playCommand:
+		return handleOwnerPlays(bot, rm, replyToken, true, source, config.LiffRoomID)
+	case moderateCommand:
+		return handleOwnerPlays(bot, rm, replyToken, false, source, config.LiffRoomID)
+	}
 
-		m1 := messaging_api.TextMessage{Text: "查無此活動"}
-		return reply(bot, replyToken, m1)
+	if inviteNo, ok := rm.ParseInviteNo(text); ok {
+		return handleJoin(bot, rm, replyToken, inviteNo, source, config.LiffRoomID)
 	}
 
-	return errors.New("Unknown message text " + text)
+	// Typos and chatter get a hint instead of silence
+	m1 := messaging_api.TextMessage{Text: unknownTextMessage}
+	return reply(bot, replyToken, m1)
 }
 
-func handleImage(bot *messaging_api.MessagingApiAPI, replyToken string, message *webhook.ImageMessageContent, source webhook.UserSource) error {
+// handleImage creates a round from the role config encoded in the URL of an image message.
+//
+// Deprecated: this is the legacy path of old LIFF pages; the setting page now calls POST /api/rounds.
+func handleImage(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, message *webhook.ImageMessageContent, source webhook.UserSource, botBasicID string) error {
 	u := message.ContentProvider.OriginalContentUrl
 
 	url, err := url.Parse(u)
@@ -159,144 +194,69 @@ func handleImage(bot *messaging_api.MessagingApiAPI, replyToken string, message
 	switch q.Get("m") {
 	case "settingRole":
 
-		// Generate inviteNo
-		randomNo, err := domain.Rng.IntN(999999)
+		inviteNo, err := rm.NewInviteNo()
 		if err != nil {
-			log.Println("Error generating random inviteNo: ", err)
 			return err
 		}
-		inviteNo := fmt.Sprintf("%06d", randomNo)
-		if isRoundInviteNoDuplicate(inviteNo) {
-			log.Println("inviteNo duplicate: " + inviteNo)
-			m1 := messaging_api.TextMessage{Text: "創建失敗，請重新嘗試"}
-			return reply(bot, replyToken, m1)
-		}
-		// Create round and set identity
-		round := domain.NewRound(source.UserId, inviteNo)
-		if v := q.Get("b1"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with b1: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.WerewolfKing, n)
-		}
-		if v := q.Get("b2"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with b2: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.WhiteWerewolf, n)
-		}
-		if v := q.Get("b3"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with b3: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.GhostRider, n)
-		}
-		if v := q.Get("b4"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with b4: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.WerewolfBeauty, n)
-		}
-		if v := q.Get("b0"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with b0: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.Werewolf, n)
-		}
-		if v := q.Get("g1"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with g1: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.Seer, n)
-		}
-		if v := q.Get("g2"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with g2: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.Witch, n)
-		}
-		if v := q.Get("g3"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with g3: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.Hunter, n)
-		}
-		if v := q.Get("g4"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with g4: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.Guard, n)
-		}
-		if v := q.Get("g5"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with g5: ", err)
-				return err
+		// Read role counts and validate the board
+		counts := make(map[domain.Identity]int)
+		for _, role := range domain.Roles() {
+			v := q.Get(role.QueryKey)
+			if v == "" {
+				continue
 			}
-			round.SetIdentity(source.UserId, domain.Knight, n)
-		}
-		if v := q.Get("g6"); v != "" {
 			n, err := strconv.Atoi(v)
 			if err != nil {
-				log.Println("parse error with g6: ", err)
+				log.Printf("parse error with %s: %v", role.QueryKey, err)
 				return err
 			}
-			round.SetIdentity(source.UserId, domain.Magician, n)
+			counts[role.Identity] = n
 		}
-		if v := q.Get("g0"); v != "" {
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				log.Println("parse error with g0: ", err)
-				return err
-			}
-			round.SetIdentity(source.UserId, domain.Villager, n)
+		warnings, err := domain.ValidateBoard(counts)
+		var boardErr *domain.BoardError
+		if errors.As(err, &boardErr) {
+			m1 := messaging_api.TextMessage{Text: boardErrorMessage(boardErr)}
+			return reply(bot, replyToken, m1)
 		}
 
-		rounds[source.UserId] = round
+		// Create round and set identity
+		round := newRoundFromCounts(source.UserId, inviteNo, counts)
+		round.Rules = parseRules(q.Get("tie"), q.Get("win"))
 
-		m1 := messaging_api.TextMessage{Text: "成功創建房間編號為: " + inviteNo}
-		return reply(bot, replyToken, m1)
+		return createRound(bot, rm, replyToken, round, botBasicID, warnings...)
 	}
 	return errors.New("Unknown url query key " + q.Get("m"))
 }
 
 func handlePostbackEvent(bot *messaging_api.MessagingApiAPI,
+	rm *usecase.RoundManager,
+	tm *usecase.TemplateManager,
 	replyToken string,
 	postback *webhook.PostbackContent,
 	source webhook.UserSource,
-	liffID string,
+	config internal.BotConfig,
 ) error {
+	botBasicID := config.LineBotBasicID
+
 	switch postback.Data {
 	case EventCreate:
 
-		delete(rounds, source.UserId)
+		rm.Expire(source.UserId)
 
-		return reply(bot, replyToken, ModeSettingTemplateV2(liffID))
+		return reply(bot, replyToken, ModeSettingTemplateV2(config.LiffID))
 
 	case EventLook:
 
-		if r, ok := rounds[source.UserId]; ok {
-			m1 := messaging_api.TextMessage{Text: "房間編號為: " + r.InviteNo}
-			m2 := messaging_api.TextMessage{Text: r.GetParticipantsInfoReplyMessage(source.UserId)}
-			return reply(bot, replyToken, m1, m2)
+		if inviteNo, info, err := rm.Look(source.UserId); err == nil {
+			if info == "" {
+				info = "你正在參與這局遊戲，無法查看其他人的身分"
+			}
+			m1 := messaging_api.TextMessage{Text: "房間編號為: " + inviteNo}
+			m3 := messaging_api.TextMessage{Text: info, QuickReply: StartGameQuickReply(roomDashboardURL(config.LiffRoomID, inviteNo))}
+			if summary, err := rm.FindByInviteNo(inviteNo); err == nil {
+				return reply(bot, replyToken, m1, InviteTemplate(summary, botBasicID), m3)
+			}
+			return reply(bot, replyToken, m1, m3)
 		}
 
 		m1 := messaging_api.TextMessage{Text: "...目前沒有開設房間\n請先開設房間喔"}
@@ -304,36 +264,112 @@ func handlePostbackEvent(bot *messaging_api.MessagingApiAPI,
 
 	case EventAgain:
 
-		if r, ok := rounds[source.UserId]; ok {
-			r.Again()
-			m1 := messaging_api.TextMessage{Text: "已經重新發牌囉!"}
+		inviteNo, err := rm.HostedInviteNo(source.UserId)
+		if err == nil {
+			err = rm.Again(inviteNo, source.UserId)
+		}
+		switch {
+		case errors.Is(err, usecase.ErrRoundNotFound):
+			m1 := messaging_api.TextMessage{Text: "...目前沒有開設房間\n請先開設房間喔"}
 			return reply(bot, replyToken, m1)
+		case err != nil:
+			return err
 		}
-
-		m1 := messaging_api.TextMessage{Text: "...目前沒有開設房間\n請先開設房間喔"}
+		m1 := messaging_api.TextMessage{Text: "已經重新發牌囉!"}
 		return reply(bot, replyToken, m1)
+
+	case EventStart:
+
+		return handleStartGame(bot, rm, replyToken, source)
+
+	case EventPreset:
+
+		return reply(bot, replyToken, PresetListTemplate())
+	}
+
+	if q, err := url.ParseQuery(postback.Data); err == nil {
+		switch q.Get("e") {
+		case EventGame:
+			return handleGamePostback(bot, rm, replyToken, q, source)
+		case EventPreset:
+			return handleCreateFromPreset(bot, rm, replyToken, q.Get("p"), source, botBasicID)
+		case EventTemplate:
+			return handleCreateFromTemplate(bot, tm, replyToken, q.Get("n"), source, botBasicID)
+		case EventJoin:
+			return handleJoin(bot, rm, replyToken, q.Get("i"), source, config.LiffRoomID)
+		case EventLeave:
+			return handleLeave(bot, rm, replyToken, q.Get("i"), source)
+		case EventKick, EventReplace:
+			return handleMemberChange(bot, rm, replyToken, q.Get("e"), q.Get("i"), q.Get("u"), source)
+		case EventGrant, EventRevoke:
+			return handleCoHostChange(bot, rm, replyToken, q.Get("e"), q.Get("i"), q.Get("u"), source)
+		}
 	}
 
 	return errors.New("Unknown event key " + postback.Data)
 }
 
-func isRoundInviteNoDuplicate(inviteNo string) bool {
-	for _, r := range rounds {
-		if r.InviteNo == inviteNo {
-			return true
-		}
+// parseRules reads the house rules chosen on the LIFF setting page; unknown values keep the defaults.
+func parseRules(tie, win string) domain.Rules {
+	rules := domain.DefaultRules()
+	if tie == "none" {
+		rules.TieRule = domain.TieNoExile
+	}
+	if win == "all" {
+		rules.WinRule = domain.WinAllKill
+	}
+	return rules
+}
+
+// createRound stores a new round and replies with its invite number and any warnings about the board.
+func createRound(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, round *domain.Round, botBasicID string, warnings ...domain.Issue) error {
+	if err := rm.Create(round); err != nil {
+		log.Println("inviteNo duplicate: " + round.InviteNo)
+		m1 := messaging_api.TextMessage{Text: "創建失敗，請重新嘗試"}
+		return reply(bot, replyToken, m1)
 	}
-	return false
+	return reply(bot, replyToken, roundCreatedMessages(round, botBasicID, warnings)...)
 }
 
-// Return round owner ID
-func findRoundByInviteNo(inviteNo string) string {
-	for id, r := range rounds {
-		if r.InviteNo == inviteNo {
-			return id
+// newRoundFromCounts creates a round dealing the given number of cards per identity, in role registry order.
+func newRoundFromCounts(ownerID, inviteNo string, counts map[domain.Identity]int) *domain.Round {
+	round := domain.NewRound(ownerID, inviteNo)
+	for _, role := range domain.Roles() {
+		if n := counts[role.Identity]; n > 0 {
+			round.SetIdentity(ownerID, role.Identity, n)
 		}
 	}
-	return ""
+	return round
+}
+
+// roundCreatedMessages announces a new round's invite number and the balance score of its board,
+// followed by the warnings about the board and the invite card the owner can share.
+func roundCreatedMessages(round *domain.Round, botBasicID string, warnings []domain.Issue) []messaging_api.MessageInterface {
+	balance := domain.ScoreBoard(domain.IdentityCounts(round.Identities))
+	messages := []messaging_api.MessageInterface{
+		messaging_api.TextMessage{Text: "成功創建房間編號為: " + round.InviteNo + "\n板子平衡評估: " + balance.String()},
+	}
+	if len(warnings) > 0 {
+		messages = append(messages, messaging_api.TextMessage{Text: issueList("提醒，這個板子可能不太平衡:", warnings)})
+	}
+	summary := usecase.RoundSummary{OwnerID: round.OwnerID, InviteNo: round.InviteNo, Identities: round.Identities, Participants: len(round.Participants)}
+	return append(messages, InviteTemplate(summary, botBasicID))
+}
+
+// boardErrorMessage explains to the owner everything wrong with a board.
+func boardErrorMessage(err *domain.BoardError) string {
+	return issueList("房間設定有誤，請修正後重新送出:", err.Issues)
+}
+
+// issueList renders the issues as a bulleted list under the title.
+func issueList(title string, issues []domain.Issue) string {
+	var sb strings.Builder
+	sb.WriteString(title)
+	for _, i := range issues {
+		sb.WriteString("\n・")
+		sb.WriteString(i.Message)
+	}
+	return sb.String()
 }
 
 func reply(bot *messaging_api.MessagingApiAPI, replyToken string, msg ...messaging_api.MessageInterface) error {
@@ -351,6 +387,32 @@ func reply(bot *messaging_api.MessagingApiAPI, replyToken string, msg ...messagi
 	return nil
 }
 
+// pushMessage sends messages to a single user outside of a reply.
+func pushMessage(bot *messaging_api.MessagingApiAPI, to string, msg ...messaging_api.MessageInterface) error {
+	if _, err := bot.PushMessage(
+		&messaging_api.PushMessageRequest{
+			To:       to,
+			Messages: msg,
+		}, "",
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// multicast sends messages to several users at once.
+func multicast(bot *messaging_api.MessagingApiAPI, to []string, msg ...messaging_api.MessageInterface) error {
+	if _, err := bot.Multicast(
+		&messaging_api.MulticastRequest{
+			To:       to,
+			Messages: msg,
+		}, "",
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
 func push(bot *messaging_api.MessagingApiAPI, eventType string, source webhook.UserSource, discordBotToken, discordChannelID string) error {
 	profile, err := bot.GetProfile(source.UserId)
 	if err != nil {
@@ -0,0 +1,120 @@
+package router
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// handleStartGame starts the game engine of the round the user hosts, moderated by the user.
+func handleStartGame(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, source webhook.UserSource) error {
+	inviteNo, err := rm.HostedInviteNo(source.UserId)
+	var summary usecase.RoundSummary
+	if err == nil {
+		summary, err = rm.FindByInviteNo(inviteNo)
+	}
+	var update usecase.GameUpdate
+	if err == nil {
+		update, err = rm.StartGame(inviteNo, source.UserId)
+	}
+	switch {
+	case errors.Is(err, usecase.ErrRoundNotFound):
+		m1 := messaging_api.TextMessage{Text: "...目前沒有開設房間\n請先開設房間喔"}
+		return reply(bot, replyToken, m1)
+	case errors.Is(err, domain.ErrNotPermitted):
+		m1 := messaging_api.TextMessage{Text: "你正在參與這局遊戲，請由主持人開始遊戲"}
+		return reply(bot, replyToken, m1)
+	case errors.Is(err, domain.ErrRegistrationOpen):
+		m1 := messaging_api.TextMessage{Text: "尚未額滿，無法開始遊戲"}
+		return reply(bot, replyToken, m1)
+	case err != nil:
+		return err
+	}
+	return deliverGameUpdate(bot, summary.OwnerID, update)
+}
+
+// handleGamePostback performs the game action encoded in the postback query.
+func handleGamePostback(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, q url.Values, source webhook.UserSource) error {
+	action, err := strconv.Atoi(q.Get("a"))
+	if err != nil {
+		return err
+	}
+	ownerID := q.Get("r")
+	// Buttons sent before turns were encoded never match a turn, so they expire too.
+	turn, err := strconv.Atoi(q.Get("n"))
+	if err != nil {
+		turn = -1
+	}
+
+	update, err := rm.Act(ownerID, source.UserId, turn, domain.Action(action), q.Get("t"))
+	if err != nil {
+		if msg := gameErrorMessage(err); msg != "" {
+			m1 := messaging_api.TextMessage{Text: msg}
+			return reply(bot, replyToken, m1)
+		}
+		return err
+	}
+	return deliverGameUpdate(bot, ownerID, update)
+}
+
+// gameErrorMessage returns the reply for a rejected game action, or "" for unexpected errors.
+func gameErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, usecase.ErrRoundNotFound), errors.Is(err, usecase.ErrGameNotStarted):
+		return "查無此遊戲"
+	case errors.Is(err, domain.ErrGameEnded):
+		return "遊戲已結束"
+	case errors.Is(err, usecase.ErrPromptExpired):
+		return "這個選項已經過期了，請使用最新的提示"
+	case errors.Is(err, domain.ErrNotPermitted):
+		return "只有主持人可以開始投票"
+	case errors.Is(err, domain.ErrNotYourTurn):
+		return "現在不是你的回合"
+	case errors.Is(err, domain.ErrInvalidTarget):
+		return "無法選擇這位玩家"
+	case errors.Is(err, domain.ErrPotionUsed):
+		return "藥水已經使用過了"
+	case errors.Is(err, domain.ErrShotPending):
+		return "請等待獵人開槍"
+	case errors.Is(err, domain.ErrAlreadyVoted):
+		return "你已經投過票了"
+	}
+	return ""
+}
+
+// deliverGameUpdate pushes public notices to the round's group chat or else to the audience,
+// private notices to their recipient and prompts to the players who should act.
+func deliverGameUpdate(bot *messaging_api.MessagingApiAPI, ownerID string, update usecase.GameUpdate) error {
+	var public []string
+	for _, n := range update.Notices {
+		if n.To == "" {
+			public = append(public, n.Text)
+			continue
+		}
+		if err := pushMessage(bot, n.To, messaging_api.TextMessage{Text: n.Text}); err != nil {
+			return err
+		}
+	}
+	if len(public) > 0 {
+		m1 := messaging_api.TextMessage{Text: strings.Join(public, "\n")}
+		if update.GroupID != "" {
+			if err := pushMessage(bot, update.GroupID, m1); err != nil {
+				return err
+			}
+		} else if err := multicast(bot, update.Audience, m1); err != nil {
+			return err
+		}
+	}
+	for _, p := range update.Prompts {
+		if err := pushMessage(bot, p.UserID, PromptTemplate(ownerID, p)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package router
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// Commands understood in group and multi-person chats.
+const (
+	openCommand = "/開房" // Binds the sender's round to the chat.
+	joinCommand = "/加入" // Joins the round bound to the chat.
+)
+
+// groupWelcomeText is sent when the bot is invited into a chat.
+const groupWelcomeText = "大家好！房主請先私訊我開設房間，再回到這裡輸入 " + openCommand + "\n其他人按下加入按鈕或輸入 " + joinCommand + " 就能參加，身分會私訊給你"
+
+// chatSource returns the group or multi-person chat of an event source and the user in it.
+// The user ID is empty when LINE does not disclose it.
+func chatSource(source webhook.SourceInterface) (chatID, userID string, ok bool) {
+	switch s := source.(type) {
+	case webhook.GroupSource:
+		return s.GroupId, s.UserId, true
+	case webhook.RoomSource:
+		return s.RoomId, s.UserId, true
+	}
+	return "", "", false
+}
+
+// sourceUserID returns the user behind an event source, empty when LINE does not disclose it.
+func sourceUserID(source webhook.SourceInterface) string {
+	if s, ok := source.(webhook.UserSource); ok {
+		return s.UserId
+	}
+	_, userID, _ := chatSource(source)
+	return userID
+}
+
+// memberProfile looks up a member of a group or multi-person chat.
+// Unlike GetProfile, it also works for members who have not added the bot as a friend.
+func memberProfile(bot *messaging_api.MessagingApiAPI, source webhook.SourceInterface) (name, pictureURL string, err error) {
+	switch s := source.(type) {
+	case webhook.GroupSource:
+		p, err := bot.GetGroupMemberProfile(s.GroupId, s.UserId)
+		if err != nil {
+			return "", "", err
+		}
+		return p.DisplayName, p.PictureUrl, nil
+	case webhook.RoomSource:
+		p, err := bot.GetRoomMemberProfile(s.RoomId, s.UserId)
+		if err != nil {
+			return "", "", err
+		}
+		return p.DisplayName, p.PictureUrl, nil
+	}
+	return "", "", errors.New("not a chat source")
+}
+
+// handleGroupText handles the commands typed in a group or multi-person chat.
+// Any other text is chatter between players and is ignored.
+func handleGroupText(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, message *webhook.TextMessageContent, source webhook.SourceInterface, liffRoomID string) error {
+	switch strings.TrimSpace(message.Text) {
+	case openCommand:
+		return handleOpenCommand(bot, rm, replyToken, source)
+	case joinCommand:
+		return handleGroupJoin(bot, rm, replyToken, "", source, liffRoomID)
+	}
+	return nil
+}
+
+// handleGroupPostback handles the postbacks of the buttons posted in a group or multi-person chat.
+func handleGroupPostback(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, postback *webhook.PostbackContent, source webhook.SourceInterface, liffRoomID string) error {
+	if q, err := url.ParseQuery(postback.Data); err == nil && q.Get("e") == EventJoin {
+		return handleGroupJoin(bot, rm, replyToken, q.Get("i"), source, liffRoomID)
+	}
+	return errors.New("Unknown group event key " + postback.Data)
+}
+
+// handleOpenCommand binds the sender's round to the chat and posts the join button.
+func handleOpenCommand(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, source webhook.SourceInterface) error {
+	chatID, userID, _ := chatSource(source)
+	if userID == "" {
+		m1 := messaging_api.TextMessage{Text: "無法取得你的帳號，請先加我為好友"}
+		return reply(bot, replyToken, m1)
+	}
+
+	gr, err := rm.BindGroup(userID, chatID)
+	switch {
+	case errors.Is(err, usecase.ErrRoundNotFound):
+		m1 := messaging_api.TextMessage{Text: "你還沒有開設房間\n請先私訊我開設房間，再回到這裡輸入 " + openCommand}
+		return reply(bot, replyToken, m1)
+	case err != nil:
+		return err
+	}
+	return reply(bot, replyToken, GroupJoinTemplate(gr))
+}
+
+// handleGroupJoin registers the sender into the round with the given invite number,
+// or into the round bound to the chat when inviteNo is empty.
+// The identity is pushed privately; the chat only learns that the player joined.
+func handleGroupJoin(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken, inviteNo string, source webhook.SourceInterface, liffRoomID string) error {
+	chatID, userID, _ := chatSource(source)
+	if userID == "" {
+		m1 := messaging_api.TextMessage{Text: "無法取得你的帳號，請先加我為好友"}
+		return reply(bot, replyToken, m1)
+	}
+
+	if inviteNo == "" {
+		gr, err := rm.FindByGroup(chatID)
+		if errors.Is(err, usecase.ErrGroupNotBound) {
+			m1 := messaging_api.TextMessage{Text: "這裡還沒有開房，請房主輸入 " + openCommand}
+			return reply(bot, replyToken, m1)
+		}
+		if err != nil {
+			return err
+		}
+		inviteNo = gr.InviteNo
+	}
+
+	name, pictureURL, err := memberProfile(bot, source)
+	if err != nil {
+		return err
+	}
+	res := rm.Join(inviteNo, userID, name, pictureURL)
+	if res.Status != usecase.JoinJoined && res.Status != usecase.JoinAlreadyJoined {
+		m1 := messaging_api.TextMessage{Text: joinRejectedMessage(res.Status, inviteNo)}
+		return reply(bot, replyToken, m1)
+	}
+
+	p := res.Participant
+	if err := pushMessage(bot, userID, roleCard(rm, inviteNo, p, liffRoomID)); err != nil {
+		log.Printf("push identity to %s error: %v", userID, err)
+		m1 := messaging_api.TextMessage{Text: name + " 已加入，但無法私訊身分給你\n請先加我為好友，再按一次加入"}
+		return reply(bot, replyToken, m1)
+	}
+
+	text := p.Label() + " 已加入 (" + joinedCount(res.Round.Participants, len(res.Round.Identities)) + ")，身分已私訊給你"
+	if res.Status == usecase.JoinAlreadyJoined {
+		text = p.Label() + " 已經加入過了，身分已再次私訊給你"
+	}
+	return reply(bot, replyToken, messaging_api.TextMessage{Text: text})
+}
+
+// handleBotJoined greets a chat the bot was invited into.
+func handleBotJoined(bot *messaging_api.MessagingApiAPI, replyToken string) error {
+	m1 := messaging_api.TextMessage{Text: groupWelcomeText}
+	return reply(bot, replyToken, m1)
+}
+
+// handleMembersJoined offers the join button to new members when a round is bound to the chat.
+func handleMembersJoined(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, source webhook.SourceInterface) error {
+	chatID, _, _ := chatSource(source)
+	gr, err := rm.FindByGroup(chatID)
+	if errors.Is(err, usecase.ErrGroupNotBound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return reply(bot, replyToken, GroupJoinTemplate(gr))
+}
+
+// joinedCount renders the registration progress, e.g. "3/9".
+func joinedCount(joined, total int) string {
+	return strconv.Itoa(joined) + "/" + strconv.Itoa(total)
+}
@@ -0,0 +1,178 @@
+package router
+
+import (
+	"errors"
+	"log"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// Text commands sharing the moderation of a round.
+const (
+	coHostCommand   = "/cohost"   // Asks the owner to co-host a round, e.g. "/cohost 123456"; alone, the owner lists the co-hosts.
+	playCommand     = "/play"     // Owner joins their round, the roles hidden from them until the game ends.
+	moderateCommand = "/moderate" // Owner moderates their round again.
+)
+
+// handleCoHostCommand asks the owner of the round with the given invite number to let the user co-host it.
+// Without an invite number, it lists the co-hosts of the user's own round.
+func handleCoHostCommand(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken, args string, source webhook.UserSource) error {
+	if args == "" {
+		return handleCoHostList(bot, rm, replyToken, source)
+	}
+	inviteNo, ok := rm.ParseInviteNo(args)
+	if !ok {
+		m1 := messaging_api.TextMessage{Text: "請輸入要協助主持的房間號碼，例如 " + coHostCommand + " 123456"}
+		return reply(bot, replyToken, m1)
+	}
+
+	summary, err := rm.FindByInviteNo(inviteNo)
+	if err != nil {
+		if msg := hostErrorMessage(err); msg != "" {
+			m1 := messaging_api.TextMessage{Text: msg}
+			return reply(bot, replyToken, m1)
+		}
+		return err
+	}
+	if summary.OwnerID == source.UserId {
+		m1 := messaging_api.TextMessage{Text: "你就是房間 " + inviteNo + " 的房主"}
+		return reply(bot, replyToken, m1)
+	}
+	user, err := bot.GetProfile(source.UserId)
+	if err != nil {
+		return err
+	}
+	if err := pushMessage(bot, summary.OwnerID, CoHostRequestTemplate(inviteNo, source.UserId, user.DisplayName)); err != nil {
+		return err
+	}
+	m1 := messaging_api.TextMessage{Text: "已向房主申請協助主持房間 " + inviteNo + "，房主同意後會通知你"}
+	return reply(bot, replyToken, m1)
+}
+
+// handleCoHostList lists the co-hosts of the user's round, offering to revoke them.
+func handleCoHostList(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, source webhook.UserSource) error {
+	inviteNo, err := rm.HostedInviteNo(source.UserId)
+	if err != nil {
+		m1 := messaging_api.TextMessage{Text: "...目前沒有開設房間\n請先開設房間喔"}
+		return reply(bot, replyToken, m1)
+	}
+	room, err := rm.Room(inviteNo, source.UserId)
+	if err != nil {
+		return err
+	}
+	if room.OwnerID != source.UserId {
+		m1 := messaging_api.TextMessage{Text: "你是房間 " + inviteNo + " 的主持人，只有房主可以管理主持人"}
+		return reply(bot, replyToken, m1)
+	}
+	return reply(bot, replyToken, CoHostListTemplate(inviteNo, room.CoHosts))
+}
+
+// handleCoHostChange grants or revokes the co-host rights of the user on behalf of the owner and tells the user.
+func handleCoHostChange(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken, event, inviteNo, userID string, source webhook.UserSource) error {
+	if event == EventRevoke {
+		if err := rm.RevokeCoHost(inviteNo, source.UserId, userID); err != nil {
+			if msg := hostErrorMessage(err); msg != "" {
+				m1 := messaging_api.TextMessage{Text: msg}
+				return reply(bot, replyToken, m1)
+			}
+			return err
+		}
+		pushText(bot, userID, "你已不再是房間 "+inviteNo+" 的主持人")
+		m1 := messaging_api.TextMessage{Text: "已移除房間 " + inviteNo + " 的主持人"}
+		return reply(bot, replyToken, m1)
+	}
+
+	user, err := bot.GetProfile(userID)
+	if err != nil {
+		return err
+	}
+	if err := rm.GrantCoHost(inviteNo, source.UserId, userID, user.DisplayName); err != nil {
+		if msg := hostErrorMessage(err); msg != "" {
+			m1 := messaging_api.TextMessage{Text: msg}
+			return reply(bot, replyToken, m1)
+		}
+		return err
+	}
+	pushText(bot, userID, "房主同意你協助主持房間 "+inviteNo+"，點選「查看房間」可以查看所有人的身分、重新發牌或開始遊戲")
+	m1 := messaging_api.TextMessage{Text: "已將 " + user.DisplayName + " 設為房間 " + inviteNo + " 的主持人"}
+	return reply(bot, replyToken, m1)
+}
+
+// handleOwnerPlays lets the owner play in their round rather than moderate it, dealing them a card,
+// or moderate again.
+func handleOwnerPlays(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken string, plays bool, source webhook.UserSource, liffRoomID string) error {
+	inviteNo, err := rm.HostedInviteNo(source.UserId)
+	var p domain.Participant
+	if err == nil && plays {
+		var user *messaging_api.UserProfileResponse
+		if user, err = bot.GetProfile(source.UserId); err != nil {
+			return err
+		}
+		p, err = rm.PlayAsOwner(inviteNo, source.UserId, user.DisplayName, user.PictureUrl)
+	} else if err == nil {
+		err = rm.SetOwnerPlays(inviteNo, source.UserId, false)
+	}
+	switch {
+	case errors.Is(err, usecase.ErrRoundNotFound):
+		m1 := messaging_api.TextMessage{Text: "...目前沒有開設房間\n請先開設房間喔"}
+		return reply(bot, replyToken, m1)
+	case errors.Is(err, domain.ErrNotPermitted):
+		m1 := messaging_api.TextMessage{Text: "只有房主可以選擇參與遊戲或主持"}
+		return reply(bot, replyToken, m1)
+	case errors.Is(err, domain.ErrAlreadyParticipant):
+		m1 := messaging_api.TextMessage{Text: "你已經加入遊戲，無法改回主持"}
+		return reply(bot, replyToken, m1)
+	case errors.Is(err, domain.ErrRoundFull):
+		m1 := messaging_api.TextMessage{Text: "房間 " + inviteNo + " 已額滿，無法加入遊戲"}
+		return reply(bot, replyToken, m1)
+	case err != nil:
+		return err
+	}
+
+	if plays {
+		m1 := messaging_api.TextMessage{Text: "你參與了房間 " + inviteNo + " 的遊戲，遊戲結束前其他人的身分不會顯示給你\n" +
+			"需要主持人時，請對方輸入 " + coHostCommand + " " + inviteNo + " 提出申請"}
+		return reply(bot, replyToken, m1, roleCard(rm, inviteNo, p, liffRoomID))
+	}
+	m1 := messaging_api.TextMessage{Text: "你已改回主持房間 " + inviteNo}
+	return reply(bot, replyToken, m1)
+}
+
+// dealPlayingOwner pushes a playing owner their new role card once the round is reshuffled,
+// since they are dealt again at once rather than by typing the invite number.
+func dealPlayingOwner(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, liffRoomID string) usecase.RoundEventHandler {
+	return func(e usecase.RoundEvent) {
+		if e.Type != usecase.RoundReshuffled {
+			return
+		}
+		p, ok := rm.PlayingOwner(e.InviteNo)
+		if !ok {
+			return
+		}
+		m1 := messaging_api.TextMessage{Text: "房間 " + e.InviteNo + " 已重新發牌，這是你的新身分"}
+		m2 := roleCard(rm, e.InviteNo, p, liffRoomID)
+		go func() {
+			if err := pushMessage(bot, p.UserID, m1, m2); err != nil {
+				log.Printf("push role card to %s error: %v", p.UserID, err)
+			}
+		}()
+	}
+}
+
+// hostErrorMessage explains why co-hosting could not be granted or revoked, or is empty for unexpected errors.
+func hostErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, usecase.ErrRoundNotFound):
+		return "查無此房間"
+	case errors.Is(err, domain.ErrNotPermitted):
+		return "只有房主可以管理主持人"
+	case errors.Is(err, domain.ErrAlreadyParticipant):
+		return "已加入遊戲的玩家不能擔任主持人"
+	case errors.Is(err, domain.ErrNotCoHost):
+		return "該使用者不是主持人"
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package router
+
+import (
+	"testing"
+	"time"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCoHost_RequestAndGrant(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 2)
+	require.NoError(t, rm.Create(round))
+	api, bot := newFakeLineAPI(t)
+	assert := assert.New(t)
+
+	require.NoError(t, handleCoHostCommand(bot, rm, "token", "000001", webhook.UserSource{UserId: "host1"}))
+	assert.Contains(api.sent("/v2/bot/message/reply")[0], "已向房主申請")
+	require.Len(t, api.sent("/v2/bot/message/push"), 1, "The owner should be asked")
+	assert.Contains(api.sent("/v2/bot/message/push")[0], "owner1")
+	assert.Contains(api.sent("/v2/bot/message/push")[0], "Player host1")
+
+	// Only the owner may agree; to anyone else the room does not exist.
+	require.NoError(t, handleCoHostChange(bot, rm, "token", EventGrant, "000001", "host1", webhook.UserSource{UserId: "host1"}))
+	assert.Contains(api.sent("/v2/bot/message/reply")[1], "查無此房間")
+
+	require.NoError(t, handleCoHostChange(bot, rm, "token", EventGrant, "000001", "host1", webhook.UserSource{UserId: "owner1"}))
+	assert.Contains(api.sent("/v2/bot/message/reply")[2], "已將 Player host1 設為房間 000001 的主持人")
+	assert.Contains(api.sent("/v2/bot/message/push")[1], "host1")
+	inviteNo, err := rm.HostedInviteNo("host1")
+	require.NoError(t, err)
+	assert.Equal("000001", inviteNo)
+
+	require.NoError(t, handleCoHostCommand(bot, rm, "token", "", webhook.UserSource{UserId: "owner1"}))
+	assert.Contains(api.sent("/v2/bot/message/reply")[3], "Player host1")
+
+	require.NoError(t, handleCoHostChange(bot, rm, "token", EventRevoke, "000001", "host1", webhook.UserSource{UserId: "owner1"}))
+	assert.Contains(api.sent("/v2/bot/message/reply")[4], "已移除")
+	_, err = rm.HostedInviteNo("host1")
+	assert.ErrorIs(err, usecase.ErrRoundNotFound)
+}
+
+func TestHandleOwnerPlays(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 2)
+	require.NoError(t, rm.Create(round))
+	api, bot := newFakeLineAPI(t)
+	assert := assert.New(t)
+
+	require.NoError(t, handleOwnerPlays(bot, rm, "token", true, webhook.UserSource{UserId: "owner1"}, ""))
+	assert.Contains(api.sent("/v2/bot/message/reply")[0], "你參與了房間 000001 的遊戲")
+	assert.Contains(api.sent("/v2/bot/message/reply")[0], "平民", "The owner should be dealt a card")
+	_, info, err := rm.Look("owner1")
+	require.NoError(t, err)
+	assert.Contains(info, "Player owner1:已加入")
+	assert.NotContains(info, "平民")
+
+	require.NoError(t, handleOwnerPlays(bot, rm, "token", false, webhook.UserSource{UserId: "owner1"}, ""))
+	assert.Contains(api.sent("/v2/bot/message/reply")[1], "無法改回主持")
+
+	require.NoError(t, handleOwnerPlays(bot, rm, "token", false, webhook.UserSource{UserId: "user1"}, ""))
+	assert.Contains(api.sent("/v2/bot/message/reply")[2], "目前沒有開設房間")
+}
+
+func TestDealPlayingOwner(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 2)
+	_, err = round.PlayAsOwner("Owner", "")
+	require.NoError(t, err)
+	require.NoError(t, rm.Create(round))
+	api, bot := newFakeLineAPI(t)
+	rm.Subscribe(dealPlayingOwner(bot, rm, ""))
+
+	require.NoError(t, rm.Again("000001", "owner1"))
+	require.Eventually(t, func() bool { return len(api.sent("/v2/bot/message/push")) == 1 }, time.Second, 10*time.Millisecond)
+	push := api.sent("/v2/bot/message/push")[0]
+	assert.Contains(t, push, `"to":"owner1"`)
+	assert.Contains(t, push, "已重新發牌")
+	assert.Contains(t, push, "平民", "The owner's new role card should be pushed")
+}
@@ -0,0 +1,59 @@
+package router
+
+import (
+	"log"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// unknownTextMessage answers free text that is neither a command nor an invite number.
+const unknownTextMessage = "看不懂這則訊息耶\n" +
+	"・加入遊戲: 輸入房間號碼，或點選房主分享的「加入遊戲」按鈕\n" +
+	"・開設房間: 點選下方選單，或輸入 /preset 使用預設板子\n" +
+	"・已儲存的板子: 輸入 /template\n" +
+	"・退出房間: 輸入 /leave 房間號碼；房主可輸入 /kick 或 /replace 移出、替換玩家\n" +
+	"・協助主持: 輸入 /cohost 房間號碼 向房主申請；房主可輸入 /play 參與遊戲"
+
+// handleJoin registers the user into the round with the given invite number and replies with their role card.
+// Invite numbers arrive typed, through the invite deep link, or in the postback of the invite card.
+func handleJoin(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken, inviteNo string, source webhook.UserSource, liffRoomID string) error {
+	user, err := bot.GetProfile(source.UserId)
+	if err != nil {
+		return err
+	}
+
+	res := rm.Join(inviteNo, source.UserId, user.DisplayName, user.PictureUrl)
+	switch res.Status {
+	case usecase.JoinJoined:
+		return reply(bot, replyToken, roleCard(rm, inviteNo, res.Participant, liffRoomID))
+	case usecase.JoinAlreadyJoined:
+		m1 := messaging_api.TextMessage{Text: "已註冊，你的身分是 " + res.Participant.Identity.String()}
+		return reply(bot, replyToken, m1, roleCard(rm, inviteNo, res.Participant, liffRoomID))
+	}
+	m1 := messaging_api.TextMessage{Text: joinRejectedMessage(res.Status, inviteNo)}
+	return reply(bot, replyToken, m1)
+}
+
+// roleCard renders the participant's role card with the teammates they know so far, a link to the seat picker
+// and a button to leave the round.
+func roleCard(rm *usecase.RoundManager, inviteNo string, p domain.Participant, liffRoomID string) messaging_api.MessageInterface {
+	teammates, err := rm.Teammates(inviteNo, p.UserID)
+	if err != nil {
+		log.Printf("find teammates of %s error: %v", p.UserID, err)
+	}
+	return RoleCardTemplate(p, teammates, inviteNo, seatPickerURL(liffRoomID, inviteNo))
+}
+
+// joinRejectedMessage explains why the user could not join the round with the given invite number.
+func joinRejectedMessage(status usecase.JoinStatus, inviteNo string) string {
+	switch status {
+	case usecase.JoinFull:
+		return "房間 " + inviteNo + " 已額滿"
+	case usecase.JoinExpired:
+		return "房間 " + inviteNo + " 已結束，請房主重新開設房間"
+	}
+	return "查無房間號碼 " + inviteNo + "\n請確認號碼是否正確，或請房主重新分享邀請"
+}
@@ -14,6 +14,29 @@ func RegisterLIFF(config internal.BotConfig) {
 			log.Fatalln(err)
 		}
 
+		err = t.Execute(w, config)
+		if err != nil {
+			log.Println(err)
+		}
+	})
+	http.HandleFunc("/liff/room", func(w http.ResponseWriter, r *http.Request) {
+		t, err := template.ParseFiles("internal/router/liff/room.html")
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		err = t.Execute(w, config)
+		if err != nil {
+			log.Println(err)
+		}
+	})
+	// The seat picker is a page of the room dashboard LIFF app, opened at https://liff.line.me/{LiffRoomID}/seat
+	http.HandleFunc("/liff/room/seat", func(w http.ResponseWriter, r *http.Request) {
+		t, err := template.ParseFiles("internal/router/liff/seat.html")
+		if err != nil {
+			log.Fatalln(err)
+		}
+
 		err = t.Execute(w, config)
 		if err != nil {
 			log.Println(err)
@@ -0,0 +1,147 @@
+package router
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// Text commands changing who plays in a round.
+const (
+	leaveCommand   = "/leave"   // Player leaves a round, e.g. "/leave 123456".
+	kickCommand    = "/kick"    // Owner kicks the player in a seat, e.g. "/kick 3"; alone, it lists the players.
+	replaceCommand = "/replace" // Owner hands the seat over to the next player who joins, e.g. "/replace 3".
+)
+
+// handleLeave removes the user from the round with the given invite number and tells the owner.
+func handleLeave(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken, inviteNo string, source webhook.UserSource) error {
+	if inviteNo == "" {
+		m1 := messaging_api.TextMessage{Text: "請輸入要退出的房間號碼，例如 " + leaveCommand + " 123456"}
+		return reply(bot, replyToken, m1)
+	}
+
+	summary, err := rm.FindByInviteNo(inviteNo)
+	if err == nil {
+		err = rm.Leave(inviteNo, source.UserId)
+	}
+	if err != nil {
+		if msg := memberErrorMessage(err); msg != "" {
+			m1 := messaging_api.TextMessage{Text: msg}
+			return reply(bot, replyToken, m1)
+		}
+		return err
+	}
+
+	if user, err := bot.GetProfile(source.UserId); err == nil {
+		pushText(bot, summary.OwnerID, user.DisplayName+" 已退出房間 "+inviteNo)
+	}
+	m1 := messaging_api.TextMessage{Text: "已退出房間 " + inviteNo}
+	return reply(bot, replyToken, m1)
+}
+
+// handleMemberCommand handles the owner's /kick and /replace commands on their round.
+// Without a seat number, it lists the players to pick from.
+func handleMemberCommand(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken, event, args string, source webhook.UserSource) error {
+	inviteNo, err := rm.HostedInviteNo(source.UserId)
+	if err != nil {
+		m1 := messaging_api.TextMessage{Text: "...目前沒有開設房間\n請先開設房間喔"}
+		return reply(bot, replyToken, m1)
+	}
+	room, err := rm.Room(inviteNo, source.UserId)
+	if err != nil {
+		return err
+	}
+
+	if args == "" {
+		return reply(bot, replyToken, PlayerPickerTemplate(event, inviteNo, room.Participants))
+	}
+	seat, err := strconv.Atoi(args)
+	p, ok := participantAtSeat(room.Participants, seat)
+	if err != nil || !ok {
+		m1 := messaging_api.TextMessage{Text: "查無 " + args + "號 玩家"}
+		return reply(bot, replyToken, m1, PlayerPickerTemplate(event, inviteNo, room.Participants))
+	}
+	return handleMemberChange(bot, rm, replyToken, event, inviteNo, p.UserID, source)
+}
+
+// handleMemberChange kicks or replaces a participant on behalf of the owner and tells the participant.
+func handleMemberChange(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken, event, inviteNo, userID string, source webhook.UserSource) error {
+	room, err := rm.Room(inviteNo, source.UserId)
+	var p domain.Participant
+	if err == nil {
+		var ok bool
+		if p, ok = findParticipant(room.Participants, userID); !ok {
+			err = domain.ErrNotParticipant
+		}
+	}
+	if err == nil {
+		if event == EventKick {
+			err = rm.Kick(inviteNo, source.UserId, userID)
+		} else {
+			err = rm.Replace(inviteNo, source.UserId, userID)
+		}
+	}
+	if err != nil {
+		if msg := memberErrorMessage(err); msg != "" {
+			m1 := messaging_api.TextMessage{Text: msg}
+			return reply(bot, replyToken, m1)
+		}
+		return err
+	}
+
+	if event == EventKick {
+		pushText(bot, userID, "你已被房主移出房間 "+inviteNo)
+		m1 := messaging_api.TextMessage{Text: "已將 " + p.Label() + " 移出房間"}
+		return reply(bot, replyToken, m1)
+	}
+	pushText(bot, userID, "房主已安排其他玩家接替你在房間 "+inviteNo+" 的位置")
+	m1 := messaging_api.TextMessage{Text: "下一位輸入房間號碼 " + inviteNo + " 的玩家會接替 " + p.Label() + " 的身分和座位"}
+	return reply(bot, replyToken, m1)
+}
+
+// pushText pushes a text message, only logging failures: the reply to the user who acted matters more.
+func pushText(bot *messaging_api.MessagingApiAPI, to, text string) {
+	if err := pushMessage(bot, to, messaging_api.TextMessage{Text: text}); err != nil {
+		log.Printf("notify %s error: %v", to, err)
+	}
+}
+
+// memberErrorMessage explains why a player could not leave, be kicked or be replaced, or is empty for unexpected errors.
+func memberErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, usecase.ErrRoundNotFound):
+		return "查無此房間"
+	case errors.Is(err, domain.ErrNotPermitted):
+		return "只有房主可以變更玩家"
+	case errors.Is(err, domain.ErrNotParticipant):
+		return "該玩家不在房間中"
+	case errors.Is(err, domain.ErrGameStarted):
+		return "遊戲已經開始，無法變更玩家"
+	}
+	return ""
+}
+
+// participantAtSeat finds the participant sitting in the seat.
+func participantAtSeat(participants []domain.Participant, seat int) (domain.Participant, bool) {
+	for _, p := range participants {
+		if p.Seat == seat {
+			return p, true
+		}
+	}
+	return domain.Participant{}, false
+}
+
+// findParticipant finds the participant with the user ID.
+func findParticipant(participants []domain.Participant, userID string) (domain.Participant, bool) {
+	for _, p := range participants {
+		if p.UserID == userID {
+			return p, true
+		}
+	}
+	return domain.Participant{}, false
+}
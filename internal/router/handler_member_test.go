@@ -0,0 +1,35 @@
+package router
+
+import (
+	"testing"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMemberChange_OwnerOnly(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 2)
+	require.NoError(t, rm.Create(round))
+	require.Equal(t, usecase.JoinJoined, rm.Join("000001", "user1", "User One", "").Status)
+	api, bot := newFakeLineAPI(t)
+	assert := assert.New(t)
+
+	require.NoError(t, handleMemberChange(bot, rm, "token", EventKick, "000001", "user1", webhook.UserSource{UserId: "user1"}))
+	assert.Contains(api.sent("/v2/bot/message/reply")[0], "只有房主可以變更玩家")
+
+	require.NoError(t, handleMemberCommand(bot, rm, "token", EventKick, "1", webhook.UserSource{UserId: "owner1"}))
+	assert.Contains(api.sent("/v2/bot/message/reply")[1], "已將 1號 User One 移出房間")
+	require.Len(t, api.sent("/v2/bot/message/push"), 1, "The kicked player should be told")
+	assert.Contains(api.sent("/v2/bot/message/push")[0], "user1")
+
+	room, err := rm.Room("000001", "owner1")
+	require.NoError(t, err)
+	assert.Empty(room.Participants)
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// presetCommand is the text command creating a round from a preset, e.g. "/preset 12-wolfking".
+const presetCommand = "/preset"
+
+// handlePresetCommand lists the presets, or creates a round from the preset named by key.
+func handlePresetCommand(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken, key string, source webhook.UserSource, botBasicID string) error {
+	if key == "" {
+		return reply(bot, replyToken, PresetListTemplate())
+	}
+	return handleCreateFromPreset(bot, rm, replyToken, key, source, botBasicID)
+}
+
+// handleCreateFromPreset creates a round for the user dealing the preset's identities.
+func handleCreateFromPreset(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, replyToken, key string, source webhook.UserSource, botBasicID string) error {
+	preset, err := domain.LookupPreset(key)
+	if err != nil {
+		m1 := messaging_api.TextMessage{Text: "查無此預設板子: " + key}
+		return reply(bot, replyToken, m1, PresetListTemplate())
+	}
+
+	inviteNo, err := rm.NewInviteNo()
+	if err != nil {
+		return err
+	}
+	return createRound(bot, rm, replyToken, domain.NewRoundFromPreset(source.UserId, inviteNo, preset), botBasicID)
+}
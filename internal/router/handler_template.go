@@ -0,0 +1,80 @@
+package router
+
+import (
+	"errors"
+	"strings"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// templateCommand is the text command managing saved board templates:
+//
+//	/template             lists the templates
+//	/template save 名稱    saves the current round's board
+//	/template use 名稱     creates a round from a template
+//	/template delete 名稱  deletes a template
+const templateCommand = "/template"
+
+// handleTemplateCommand runs a /template sub-command given its arguments.
+func handleTemplateCommand(bot *messaging_api.MessagingApiAPI, tm *usecase.TemplateManager, replyToken, args string, source webhook.UserSource, botBasicID string) error {
+	sub, name, _ := strings.Cut(args, " ")
+	name = strings.TrimSpace(name)
+
+	switch sub {
+	case "", "list":
+		templates, err := tm.List(source.UserId)
+		if err != nil {
+			return err
+		}
+		return reply(bot, replyToken, TemplateListTemplate(templates))
+
+	case "save":
+		tmpl, err := tm.Save(source.UserId, name)
+		if err != nil {
+			return reply(bot, replyToken, messaging_api.TextMessage{Text: templateErrorMessage(err)})
+		}
+		m1 := messaging_api.TextMessage{Text: "已儲存板子「" + tmpl.Name + "」(" + tmpl.Summary() + ")"}
+		return reply(bot, replyToken, m1)
+
+	case "use":
+		return handleCreateFromTemplate(bot, tm, replyToken, name, source, botBasicID)
+
+	case "delete":
+		if err := tm.Delete(source.UserId, name); err != nil {
+			return reply(bot, replyToken, messaging_api.TextMessage{Text: templateErrorMessage(err)})
+		}
+		m1 := messaging_api.TextMessage{Text: "已刪除板子「" + name + "」"}
+		return reply(bot, replyToken, m1)
+	}
+
+	m1 := messaging_api.TextMessage{Text: "用法:\n/template 列出板子\n/template save 名稱\n/template use 名稱\n/template delete 名稱"}
+	return reply(bot, replyToken, m1)
+}
+
+// handleCreateFromTemplate creates a round for the user from their template with the given name.
+func handleCreateFromTemplate(bot *messaging_api.MessagingApiAPI, tm *usecase.TemplateManager, replyToken, name string, source webhook.UserSource, botBasicID string) error {
+	round, err := tm.CreateRound(source.UserId, name)
+	if err != nil {
+		return reply(bot, replyToken, messaging_api.TextMessage{Text: templateErrorMessage(err)})
+	}
+
+	return reply(bot, replyToken, roundCreatedMessages(round, botBasicID, nil)...)
+}
+
+// templateErrorMessage explains a template error to the user.
+func templateErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, usecase.ErrNoRoundToTemplate):
+		return "...目前沒有開設房間\n請先開設房間再儲存板子"
+	case errors.Is(err, usecase.ErrTemplateNotFound):
+		return "查無此板子，輸入 /template 查看已儲存的板子"
+	case errors.Is(err, usecase.ErrTooManyTemplates):
+		return "板子已達上限，請先刪除不用的板子"
+	case errors.Is(err, domain.ErrTemplateNameInvalid):
+		return "板子名稱需為 1 到 20 個字"
+	}
+	return "創建失敗，請重新嘗試"
+}
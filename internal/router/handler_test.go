@@ -0,0 +1,98 @@
+package router
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"werewolve-helper/internal"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChannelSecret = "test-secret"
+
+// textEvent builds a webhook body with a text message sent by the user in a 1:1 chat.
+func textEvent(t *testing.T, userID, replyToken, text string) string {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"destination": "bot",
+		"events": []map[string]any{{
+			"type":            "message",
+			"mode":            "active",
+			"timestamp":       1700000000000,
+			"webhookEventId":  "event-" + replyToken,
+			"deliveryContext": map[string]any{"isRedelivery": false},
+			"replyToken":      replyToken,
+			"source":          map[string]any{"type": "user", "userId": userID},
+			"message":         map[string]any{"type": "text", "id": "1", "quoteToken": "q", "text": text},
+		}},
+	})
+	require.NoError(t, err)
+	return string(body)
+}
+
+// postWebhook posts the body to the webhook handler, signed with the given channel secret.
+func postWebhook(handler http.Handler, secret, body string) *httptest.ResponseRecorder {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body))
+	req.Header.Set("X-Line-Signature", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhook_OtherUsersJoinByInviteNo(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	tm := usecase.NewTemplateManager(storage.NewMemoryTemplateRepository(), rm)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 2)
+	require.NoError(t, rm.Create(round))
+
+	api, bot := newFakeLineAPI(t)
+	handler := newWebhookHandler(internal.BotConfig{LineChannelSecret: testChannelSecret}, bot, rm, tm, NewJoinLimiter(defaultJoinLimits, time.Now))
+	assert := assert.New(t)
+
+	// Neither joiner owns a round: the round is found by its invite number alone.
+	for i, userID := range []string{"user1", "user2"} {
+		rec := postWebhook(handler, testChannelSecret, textEvent(t, userID, "reply-"+userID, "000001"))
+		require.Equal(t, http.StatusOK, rec.Code)
+		replies := api.sent("/v2/bot/message/reply")
+		require.Len(t, replies, i+1)
+		assert.Contains(replies[i], "reply-"+userID)
+		assert.Contains(replies[i], "你的身分是 平民", "The joiner should get their role card")
+	}
+
+	room, err := rm.Room("000001", "owner1")
+	require.NoError(t, err)
+	require.Len(t, room.Participants, 2)
+	assert.Equal("Player user2", room.Participants[1].Name)
+
+	// The round is full now; a third player is told so.
+	postWebhook(handler, testChannelSecret, textEvent(t, "user3", "reply-user3", "000001"))
+	assert.Contains(api.sent("/v2/bot/message/reply")[2], "已額滿")
+
+	postWebhook(handler, testChannelSecret, textEvent(t, "user3", "reply-unknown", "999999"))
+	assert.Contains(api.sent("/v2/bot/message/reply")[3], "查無房間號碼 999999")
+}
+
+func TestWebhook_RejectsBadSignature(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	_, bot := newFakeLineAPI(t)
+	handler := newWebhookHandler(internal.BotConfig{LineChannelSecret: testChannelSecret}, bot, rm, nil, NewJoinLimiter(defaultJoinLimits, time.Now))
+
+	rec := postWebhook(handler, "wrong-secret", textEvent(t, "user1", "reply", "000001"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
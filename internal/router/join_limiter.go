@@ -0,0 +1,284 @@
+package router
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"werewolve-helper/internal"
+	"werewolve-helper/internal/adapter/lineauth"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// JoinLimits configures the brute-force protection of invite numbers.
+type JoinLimits struct {
+	Window      time.Duration // Sliding window of the rate limits.
+	PerUser     int           // Join attempts a user may make per window.
+	Global      int           // Join attempts all users together may make per window.
+	MaxFailures int           // Wrong invite numbers in a row before a user is locked out.
+	Lockout     time.Duration // How long a locked out user may not join any round.
+}
+
+// defaultJoinLimits let a player mistype a few times but stop anyone walking through the six-digit numbers.
+var defaultJoinLimits = JoinLimits{
+	Window:      time.Minute,
+	PerUser:     5,
+	Global:      120,
+	MaxFailures: 5,
+	Lockout:     15 * time.Minute,
+}
+
+// JoinVerdict is the limiter's decision on a join attempt.
+type JoinVerdict int
+
+// Constants for the join verdicts.
+const (
+	JoinAllowed       JoinVerdict = iota + 1 // The attempt may proceed.
+	JoinRateLimited                          // The user made too many attempts in the window.
+	JoinGlobalLimited                        // All users together made too many attempts in the window.
+	JoinLockedOut                            // The user typed too many wrong invite numbers.
+)
+
+// JoinMetrics counts the join attempts seen by the limiter, served at /metrics/join when a metrics token is set.
+type JoinMetrics struct {
+	Allowed       int64 `json:"allowed"`       // Attempts let through.
+	WrongCodes    int64 `json:"wrongCodes"`    // Attempts let through with an invite number no round uses.
+	RateLimited   int64 `json:"rateLimited"`   // Attempts rejected by the per-user limit.
+	GlobalLimited int64 `json:"globalLimited"` // Attempts rejected by the global limit.
+	LockedOut     int64 `json:"lockedOut"`     // Attempts rejected because the user is locked out.
+	Lockouts      int64 `json:"lockouts"`      // Users locked out so far.
+}
+
+// joinRecord is what the limiter remembers about a user.
+type joinRecord struct {
+	attempts    []time.Time // Attempts within the window, oldest first.
+	failures    int         // Wrong invite numbers in a row.
+	lockedUntil time.Time   // End of the lockout, zero if not locked out.
+}
+
+// JoinLimiter rate limits join attempts per user and globally, and locks out users who keep typing wrong invite numbers.
+type JoinLimiter struct {
+	mu      sync.Mutex
+	limits  JoinLimits
+	now     func() time.Time
+	users   map[string]*joinRecord // {key: userID, value: record}
+	global  []time.Time            // Attempts of all users within the window, oldest first.
+	swept   time.Time              // Last time stale records were dropped.
+	metrics JoinMetrics
+}
+
+// NewJoinLimiter creates a JoinLimiter reading the time from now.
+func NewJoinLimiter(limits JoinLimits, now func() time.Time) *JoinLimiter {
+	return &JoinLimiter{
+		limits: limits,
+		now:    now,
+		users:  make(map[string]*joinRecord),
+		swept:  now(),
+	}
+}
+
+// Allow records a join attempt by the user and decides whether it may proceed.
+// Rejected attempts do not count against the limits, and the retry duration tells when to try again.
+func (l *JoinLimiter) Allow(userID string) (JoinVerdict, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweepLocked(now)
+	rec := l.users[userID]
+	if rec == nil {
+		rec = &joinRecord{}
+		l.users[userID] = rec
+	}
+
+	if now.Before(rec.lockedUntil) {
+		l.metrics.LockedOut++
+		return JoinLockedOut, rec.lockedUntil.Sub(now)
+	}
+	rec.attempts = trimWindow(rec.attempts, now.Add(-l.limits.Window))
+	if len(rec.attempts) >= l.limits.PerUser {
+		l.metrics.RateLimited++
+		return JoinRateLimited, rec.attempts[0].Add(l.limits.Window).Sub(now)
+	}
+	l.global = trimWindow(l.global, now.Add(-l.limits.Window))
+	if len(l.global) >= l.limits.Global {
+		l.metrics.GlobalLimited++
+		return JoinGlobalLimited, l.global[0].Add(l.limits.Window).Sub(now)
+	}
+
+	rec.attempts = append(rec.attempts, now)
+	l.global = append(l.global, now)
+	l.metrics.Allowed++
+	return JoinAllowed, 0
+}
+
+// Fail records that the user's allowed attempt named no round, locking the user out after too many in a row.
+func (l *JoinLimiter) Fail(userID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.metrics.WrongCodes++
+	rec := l.users[userID]
+	if rec == nil {
+		return
+	}
+	rec.failures++
+	if rec.failures >= l.limits.MaxFailures {
+		rec.failures = 0
+		rec.lockedUntil = l.now().Add(l.limits.Lockout)
+		l.metrics.Lockouts++
+	}
+}
+
+// Check passes an attempt by the user on the invite number through the limiter and records whether it names a round.
+// Members of the round are let through uncounted, since they already know it exists.
+// Every entry point taking an invite number from a user goes through it.
+func (l *JoinLimiter) Check(rm *usecase.RoundManager, userID, inviteNo string) (JoinVerdict, time.Duration) {
+	if rm.IsMember(inviteNo, userID) {
+		return JoinAllowed, 0
+	}
+	verdict, retry := l.Allow(userID)
+	if verdict != JoinAllowed {
+		return verdict, retry
+	}
+	if rm.HasInviteNo(inviteNo) {
+		l.Succeed(userID)
+	} else {
+		l.Fail(userID)
+	}
+	return JoinAllowed, 0
+}
+
+// Succeed records that the user's allowed attempt named a round, forgiving their earlier wrong numbers.
+func (l *JoinLimiter) Succeed(userID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if rec := l.users[userID]; rec != nil {
+		rec.failures = 0
+	}
+}
+
+// Metrics returns a snapshot of the counters.
+func (l *JoinLimiter) Metrics() JoinMetrics {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.metrics
+}
+
+// sweepLocked drops, once per window, the records of users with no recent attempt, failure or lockout.
+// The caller must hold l.mu.
+func (l *JoinLimiter) sweepLocked(now time.Time) {
+	if now.Sub(l.swept) < l.limits.Window {
+		return
+	}
+	l.swept = now
+	for userID, rec := range l.users {
+		rec.attempts = trimWindow(rec.attempts, now.Add(-l.limits.Window))
+		if len(rec.attempts) == 0 && rec.failures == 0 && !now.Before(rec.lockedUntil) {
+			delete(l.users, userID)
+		}
+	}
+}
+
+// trimWindow drops the times before the start of the window.
+func trimWindow(times []time.Time, start time.Time) []time.Time {
+	i := 0
+	for i < len(times) && !times[i].After(start) {
+		i++
+	}
+	return times[i:]
+}
+
+// newJoinMetricsHandler serves the limiter's counters to callers presenting "Authorization: Bearer <token>".
+// The counters tell how close guessing is to a lockout, so they are never public.
+func newJoinMetricsHandler(limiter *JoinLimiter, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid metrics token")
+			return
+		}
+		writeJSON(w, http.StatusOK, limiter.Metrics())
+	})
+}
+
+// textHandler handles a text message sent in a 1:1 chat.
+type textHandler func(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, tm *usecase.TemplateManager, replyToken string, message *webhook.TextMessageContent, source webhook.UserSource, config internal.BotConfig) error
+
+// limitJoins wraps a text handler so that invite numbers, typed alone or after /cohost or /leave,
+// pass through the limiter. Rejected attempts are answered here and never reach the handler.
+func limitJoins(limiter *JoinLimiter, next textHandler) textHandler {
+	return func(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, tm *usecase.TemplateManager, replyToken string, message *webhook.TextMessageContent, source webhook.UserSource, config internal.BotConfig) error {
+		text := strings.TrimSpace(message.Text)
+		if cmd, args, ok := strings.Cut(text, " "); ok && (cmd == coHostCommand || cmd == leaveCommand) {
+			text = strings.TrimSpace(args)
+		}
+		if ok, err := guardInviteNo(bot, rm, limiter, replyToken, source.UserId, text); !ok {
+			return err
+		}
+		return next(bot, rm, tm, replyToken, message, source, config)
+	}
+}
+
+// limitPostback passes the invite number a postback names, if any, through the limiter.
+// It reports false once a rejected attempt has been answered, and the postback must not be handled.
+func limitPostback(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, limiter *JoinLimiter, replyToken string, postback *webhook.PostbackContent, userID string) (bool, error) {
+	q, err := url.ParseQuery(postback.Data)
+	if err != nil || !q.Has("i") {
+		return true, nil
+	}
+	return guardInviteNo(bot, rm, limiter, replyToken, userID, q.Get("i"))
+}
+
+// guardInviteNo passes the text through the limiter if it reads as an invite number, see RoundManager.ParseInviteNo.
+// Only well-formed numbers count towards a lockout, never ordinary chat.
+// It reports false once a rejected attempt has been answered.
+func guardInviteNo(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager, limiter *JoinLimiter, replyToken, userID, text string) (bool, error) {
+	inviteNo, ok := rm.ParseInviteNo(text)
+	if !ok {
+		return true, nil
+	}
+	verdict, retry := limiter.Check(rm, userID, inviteNo)
+	if verdict == JoinAllowed {
+		return true, nil
+	}
+	m1 := messaging_api.TextMessage{Text: joinLimitedMessage(verdict, retry)}
+	return false, reply(bot, replyToken, m1)
+}
+
+// limitRoomAccess wraps a room API handler so that the invite number in the path passes through the limiter.
+// Rejected attempts are answered with 429 Too Many Requests and never reach the handler.
+func limitRoomAccess(limiter *JoinLimiter, rm *usecase.RoundManager, next func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims)) func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+	return func(w http.ResponseWriter, r *http.Request, claims lineauth.Claims) {
+		if inviteNo, ok := rm.ParseInviteNo(r.PathValue("inviteNo")); ok {
+			if verdict, retry := limiter.Check(rm, claims.UserID, inviteNo); verdict != JoinAllowed {
+				w.Header().Set("Retry-After", strconv.Itoa(max(1, int(retry.Round(time.Second)/time.Second))))
+				writeError(w, http.StatusTooManyRequests, joinLimitedMessage(verdict, retry))
+				return
+			}
+		}
+		next(w, r, claims)
+	}
+}
+
+// joinLimitedMessage explains a rejected join attempt and when to try again.
+func joinLimitedMessage(verdict JoinVerdict, retry time.Duration) string {
+	wait := "請 " + strconv.Itoa(int(retry.Round(time.Minute)/time.Minute)) + " 分鐘後再試"
+	if retry < time.Minute {
+		wait = "請 " + strconv.Itoa(max(1, int(retry.Round(time.Second)/time.Second))) + " 秒後再試"
+	}
+	switch verdict {
+	case JoinLockedOut:
+		return "輸入錯誤的房間號碼太多次，" + wait
+	case JoinGlobalLimited:
+		return "目前加入遊戲的人太多了，" + wait
+	}
+	return "嘗試加入太頻繁了，" + wait
+}
@@ -0,0 +1,246 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"werewolve-helper/internal"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock the tests move by hand.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestLimiter(limits JoinLimits) (*JoinLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 2, 3, 20, 0, 0, 0, time.UTC)}
+	return NewJoinLimiter(limits, clock.Now), clock
+}
+
+func TestJoinLimiter_PerUser(t *testing.T) {
+	l, clock := newTestLimiter(JoinLimits{Window: time.Minute, PerUser: 2, Global: 100, MaxFailures: 10, Lockout: time.Hour})
+	assert := assert.New(t)
+
+	for range 2 {
+		verdict, _ := l.Allow("user1")
+		assert.Equal(JoinAllowed, verdict)
+		clock.Advance(10 * time.Second)
+	}
+	verdict, retry := l.Allow("user1")
+	assert.Equal(JoinRateLimited, verdict)
+	assert.Equal(40*time.Second, retry, "The oldest attempt leaves the window in 40s")
+
+	verdict, _ = l.Allow("user2")
+	assert.Equal(JoinAllowed, verdict, "Other users are not limited")
+
+	clock.Advance(40 * time.Second)
+	verdict, _ = l.Allow("user1")
+	assert.Equal(JoinAllowed, verdict)
+	assert.Equal(JoinMetrics{Allowed: 4, RateLimited: 1}, l.Metrics())
+}
+
+func TestJoinLimiter_Global(t *testing.T) {
+	l, clock := newTestLimiter(JoinLimits{Window: time.Minute, PerUser: 5, Global: 3, MaxFailures: 10, Lockout: time.Hour})
+	assert := assert.New(t)
+
+	for _, userID := range []string{"user1", "user2", "user3"} {
+		verdict, _ := l.Allow(userID)
+		assert.Equal(JoinAllowed, verdict)
+	}
+	verdict, _ := l.Allow("user4")
+	assert.Equal(JoinGlobalLimited, verdict)
+
+	clock.Advance(time.Minute)
+	verdict, _ = l.Allow("user4")
+	assert.Equal(JoinAllowed, verdict)
+	assert.Equal(int64(1), l.Metrics().GlobalLimited)
+}
+
+func TestJoinLimiter_Lockout(t *testing.T) {
+	l, clock := newTestLimiter(JoinLimits{Window: time.Minute, PerUser: 100, Global: 100, MaxFailures: 3, Lockout: 15 * time.Minute})
+	assert := assert.New(t)
+
+	// A right number forgives the wrong ones before it.
+	for range 2 {
+		l.Allow("user1")
+		l.Fail("user1")
+	}
+	l.Allow("user1")
+	l.Succeed("user1")
+	for range 3 {
+		verdict, _ := l.Allow("user1")
+		require.Equal(t, JoinAllowed, verdict)
+		l.Fail("user1")
+	}
+
+	verdict, retry := l.Allow("user1")
+	assert.Equal(JoinLockedOut, verdict)
+	assert.Equal(15*time.Minute, retry)
+
+	clock.Advance(15 * time.Minute)
+	verdict, _ = l.Allow("user1")
+	assert.Equal(JoinAllowed, verdict, "The lockout ends")
+
+	m := l.Metrics()
+	assert.Equal(int64(5), m.WrongCodes)
+	assert.Equal(int64(1), m.Lockouts)
+	assert.Equal(int64(1), m.LockedOut)
+}
+
+func TestJoinLimiter_SweepsIdleUsers(t *testing.T) {
+	l, clock := newTestLimiter(JoinLimits{Window: time.Minute, PerUser: 5, Global: 100, MaxFailures: 1, Lockout: time.Hour})
+
+	l.Allow("idle")
+	l.Allow("locked")
+	l.Fail("locked")
+	clock.Advance(2 * time.Minute)
+	l.Allow("user1")
+
+	assert.NotContains(t, l.users, "idle")
+	assert.Contains(t, l.users, "locked", "Locked out users are kept until the lockout ends")
+}
+
+func TestLimitJoins(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 4)
+	require.NoError(t, rm.Create(round))
+	api, bot := newFakeLineAPI(t)
+	l, _ := newTestLimiter(JoinLimits{Window: time.Minute, PerUser: 100, Global: 100, MaxFailures: 2, Lockout: 15 * time.Minute})
+
+	var handled []string
+	next := func(_ *messaging_api.MessagingApiAPI, _ *usecase.RoundManager, _ *usecase.TemplateManager, _ string, message *webhook.TextMessageContent, _ webhook.UserSource, _ internal.BotConfig) error {
+		handled = append(handled, message.Text)
+		return nil
+	}
+	send := func(text string) {
+		handler := limitJoins(l, next)
+		require.NoError(t, handler(bot, rm, nil, "token", &webhook.TextMessageContent{Text: text}, webhook.UserSource{UserId: "guesser"}, internal.BotConfig{}))
+	}
+	assert := assert.New(t)
+
+	send("hello")
+	send("123456")
+	send("654321")
+	send("000001")
+	assert.Equal([]string{"hello", "123456", "654321"}, handled, "Chatter is not limited; the lockout starts after two wrong numbers")
+	assert.Contains(api.sent("/v2/bot/message/reply")[0], "輸入錯誤的房間號碼太多次，請 15 分鐘後再試")
+	assert.Equal(int64(2), l.Metrics().WrongCodes)
+}
+
+func TestJoinMetricsHandler(t *testing.T) {
+	l, _ := newTestLimiter(defaultJoinLimits)
+	l.Allow("user1")
+	handler := newJoinMetricsHandler(l, "secret")
+
+	serve := func(auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics/join", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve("").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("Bearer wrong").Code)
+	rec := serve("Bearer secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got JoinMetrics
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, int64(1), got.Allowed)
+}
+
+func TestLimitJoins_Commands(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 4)
+	require.NoError(t, rm.Create(round))
+	api, bot := newFakeLineAPI(t)
+	l, _ := newTestLimiter(JoinLimits{Window: time.Minute, PerUser: 100, Global: 100, MaxFailures: 2, Lockout: 15 * time.Minute})
+	handler := limitJoins(l, handleText)
+	send := func(userID, text string) string {
+		require.NoError(t, handler(bot, rm, nil, "token", &webhook.TextMessageContent{Text: text}, webhook.UserSource{UserId: userID}, internal.BotConfig{}))
+		replies := api.sent("/v2/bot/message/reply")
+		return replies[len(replies)-1]
+	}
+	assert := assert.New(t)
+
+	// Leaving a round one has no part in reads the same as leaving a round that does not exist.
+	assert.Contains(send("guesser", "/leave 123456"), "查無此房間")
+	assert.Contains(send("guesser", "/cohost 654321"), "查無此房間")
+	assert.Contains(send("guesser", "/leave 000001"), "輸入錯誤的房間號碼太多次", "Wrong numbers after any command lock the user out")
+	assert.Contains(send("guesser", "/cohost 000001"), "輸入錯誤的房間號碼太多次")
+	assert.Empty(api.sent("/v2/bot/message/push"), "The owner is not asked while the guesser is locked out")
+
+	assert.Contains(send("other", "/leave 000001"), "查無此房間")
+	assert.Contains(send("other", "/leave 999999"), "查無此房間")
+}
+
+func TestLimitPostback(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 4)
+	require.NoError(t, rm.Create(round))
+	api, bot := newFakeLineAPI(t)
+	l, _ := newTestLimiter(JoinLimits{Window: time.Minute, PerUser: 100, Global: 100, MaxFailures: 2, Lockout: 15 * time.Minute})
+	check := func(userID, data string) bool {
+		ok, err := limitPostback(bot, rm, l, "token", &webhook.PostbackContent{Data: data}, userID)
+		require.NoError(t, err)
+		return ok
+	}
+	assert := assert.New(t)
+
+	assert.True(check("guesser", EventCreate), "Postbacks without an invite number are not limited")
+	assert.True(check("guesser", joinPostbackData("123456")))
+	assert.True(check("guesser", "e=leave&i=654321"))
+	assert.False(check("guesser", joinPostbackData("000001")), "Forged join postbacks count towards the lockout")
+	assert.Contains(api.sent("/v2/bot/message/reply")[0], "輸入錯誤的房間號碼太多次")
+
+	for range 5 {
+		assert.True(check("owner1", "e=kick&i=000001&u=user1"), "Hosts using their own round are never limited")
+	}
+}
+
+func TestLimitJoins_IgnoresChatter(t *testing.T) {
+	rm, err := usecase.NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Villager, 4)
+	require.NoError(t, rm.Create(round))
+	api, bot := newFakeLineAPI(t)
+	l, _ := newTestLimiter(JoinLimits{Window: time.Minute, PerUser: 100, Global: 100, MaxFailures: 2, Lockout: 15 * time.Minute})
+	handler := limitJoins(l, handleText)
+	assert := assert.New(t)
+
+	for range 5 {
+		require.NoError(t, handler(bot, rm, nil, "token", &webhook.TextMessageContent{Text: "hahahaha"}, webhook.UserSource{UserId: "user1"}, internal.BotConfig{}))
+	}
+	for _, r := range api.sent("/v2/bot/message/reply") {
+		assert.NotContains(r, "查無房間號碼")
+		assert.Contains(r, "看不懂這則訊息")
+	}
+	assert.Zero(l.Metrics().WrongCodes, "Chatter should not use up the failure budget")
+
+	require.NoError(t, handler(bot, rm, nil, "token", &webhook.TextMessageContent{Text: "000001"}, webhook.UserSource{UserId: "user1"}, internal.BotConfig{}))
+	replies := api.sent("/v2/bot/message/reply")
+	assert.NotContains(replies[len(replies)-1], "太多次")
+}
@@ -1,6 +1,16 @@
 package router
 
-import "github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+import (
+	"cmp"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
 
 func ModeSettingTemplateV2(liffID string) messaging_api.MessageInterface {
 	return &messaging_api.TemplateMessage{
@@ -10,7 +20,326 @@ func ModeSettingTemplateV2(liffID string) messaging_api.MessageInterface {
 			Text:  "請點擊開始設定",
 			Actions: []messaging_api.ActionInterface{
 				messaging_api.UriAction{Label: "開始設定", Uri: "https://liff.line.me/" + liffID},
+				messaging_api.PostbackAction{Label: "使用預設板子", Data: EventPreset, DisplayText: "使用預設板子"},
+			},
+		},
+	}
+}
+
+// PresetListTemplate lists the preset boards with a quick reply button to create a round from each.
+func PresetListTemplate() messaging_api.MessageInterface {
+	var sb strings.Builder
+	sb.WriteString("請選擇預設板子，或輸入 /preset 代號")
+	var items []messaging_api.QuickReplyItem
+	for _, p := range domain.Presets() {
+		sb.WriteString("\n")
+		sb.WriteString(p.Key)
+		sb.WriteString(": ")
+		sb.WriteString(p.Name)
+		sb.WriteString(" (")
+		sb.WriteString(domain.CompositionSummary(p.Identities()))
+		sb.WriteString(")")
+		if len(items) < maxQuickReplyItems {
+			items = append(items, messaging_api.QuickReplyItem{
+				Action: &messaging_api.PostbackAction{
+					Label:       truncateLabel(p.Name),
+					Data:        url.Values{"e": {EventPreset}, "p": {p.Key}}.Encode(),
+					DisplayText: p.Name,
+				},
+			})
+		}
+	}
+	return &messaging_api.TextMessage{
+		Text:       sb.String(),
+		QuickReply: &messaging_api.QuickReply{Items: items},
+	}
+}
+
+// TemplateListTemplate lists the owner's saved templates with a quick reply button to create a round from each.
+func TemplateListTemplate(templates []*domain.BoardTemplate) messaging_api.MessageInterface {
+	if len(templates) == 0 {
+		return &messaging_api.TextMessage{Text: "還沒有儲存的板子\n開設房間後輸入 /template save 名稱 即可儲存"}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("已儲存的板子:")
+	var items []messaging_api.QuickReplyItem
+	for _, t := range templates {
+		sb.WriteString("\n")
+		sb.WriteString(t.Name)
+		sb.WriteString(" (")
+		sb.WriteString(t.Summary())
+		sb.WriteString(")")
+		if len(items) < maxQuickReplyItems {
+			items = append(items, messaging_api.QuickReplyItem{
+				Action: &messaging_api.PostbackAction{
+					Label:       truncateLabel(t.Name),
+					Data:        url.Values{"e": {EventTemplate}, "n": {t.Name}}.Encode(),
+					DisplayText: "/template use " + t.Name,
+				},
+			})
+		}
+	}
+	return &messaging_api.TextMessage{
+		Text:       sb.String(),
+		QuickReply: &messaging_api.QuickReply{Items: items},
+	}
+}
+
+// PlayerPickerTemplate lists the players of the owner's round by seat, with a quick reply button
+// to kick (EventKick) or replace (EventReplace) each.
+func PlayerPickerTemplate(event, inviteNo string, participants []domain.Participant) messaging_api.MessageInterface {
+	command, verb := kickCommand, "移出"
+	if event == EventReplace {
+		command, verb = replaceCommand, "替換"
+	}
+	if len(participants) == 0 {
+		return &messaging_api.TextMessage{Text: "房間 " + inviteNo + " 還沒有玩家加入"}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("請選擇要" + verb + "的玩家，或輸入 " + command + " 座號")
+	var items []messaging_api.QuickReplyItem
+	seated := slices.SortedStableFunc(slices.Values(participants), func(a, b domain.Participant) int { return cmp.Compare(a.Seat, b.Seat) })
+	for _, p := range seated {
+		sb.WriteString("\n")
+		sb.WriteString(p.Label())
+		if len(items) < maxQuickReplyItems {
+			items = append(items, messaging_api.QuickReplyItem{
+				Action: &messaging_api.PostbackAction{
+					Label:       truncateLabel(p.Label()),
+					Data:        url.Values{"e": {event}, "i": {inviteNo}, "u": {p.UserID}}.Encode(),
+					DisplayText: command + " " + strconv.Itoa(p.Seat),
+				},
+			})
+		}
+	}
+	return &messaging_api.TextMessage{
+		Text:       sb.String(),
+		QuickReply: &messaging_api.QuickReply{Items: items},
+	}
+}
+
+// CoHostRequestTemplate asks the owner whether the user may co-host the round, with a button to agree.
+func CoHostRequestTemplate(inviteNo, userID, name string) messaging_api.MessageInterface {
+	return &messaging_api.TextMessage{
+		Text: name + " 想協助主持房間 " + inviteNo + "\n主持人可以查看所有人的身分、重新發牌和開始遊戲",
+		QuickReply: &messaging_api.QuickReply{Items: []messaging_api.QuickReplyItem{{
+			Action: &messaging_api.PostbackAction{
+				Label:       "同意",
+				Data:        url.Values{"e": {EventGrant}, "i": {inviteNo}, "u": {userID}}.Encode(),
+				DisplayText: "同意 " + name + " 協助主持",
+			},
+		}}},
+	}
+}
+
+// CoHostListTemplate lists the co-hosts of the round with buttons to revoke them.
+func CoHostListTemplate(inviteNo string, coHosts []domain.CoHost) messaging_api.MessageInterface {
+	if len(coHosts) == 0 {
+		return &messaging_api.TextMessage{Text: "房間 " + inviteNo + " 目前沒有主持人\n請想協助主持的人輸入 " + coHostCommand + " " + inviteNo + " 提出申請"}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("房間 " + inviteNo + " 的主持人，點選即可移除")
+	var items []messaging_api.QuickReplyItem
+	for _, c := range coHosts {
+		sb.WriteString("\n")
+		sb.WriteString(c.Name)
+		if len(items) < maxQuickReplyItems {
+			items = append(items, messaging_api.QuickReplyItem{
+				Action: &messaging_api.PostbackAction{
+					Label:       truncateLabel("移除 " + c.Name),
+					Data:        url.Values{"e": {EventRevoke}, "i": {inviteNo}, "u": {c.UserID}}.Encode(),
+					DisplayText: "移除主持人 " + c.Name,
+				},
+			})
+		}
+	}
+	return &messaging_api.TextMessage{
+		Text:       sb.String(),
+		QuickReply: &messaging_api.QuickReply{Items: items},
+	}
+}
+
+// GroupJoinTemplate announces the round bound to a group chat with a button to join it.
+func GroupJoinTemplate(gr usecase.RoundSummary) messaging_api.MessageInterface {
+	return &messaging_api.TemplateMessage{
+		AltText: "房間 " + gr.InviteNo + " 開放加入",
+		Template: &messaging_api.ButtonsTemplate{
+			Title: "房間 " + gr.InviteNo,
+			Text:  domain.CompositionSummary(gr.Identities) + "\n目前 " + joinedCount(gr.Participants, len(gr.Identities)) + " 人，身分會私訊給你",
+			Actions: []messaging_api.ActionInterface{
+				messaging_api.PostbackAction{
+					Label:       "加入遊戲",
+					Data:        joinPostbackData(gr.InviteNo),
+					DisplayText: "加入遊戲",
+				},
+			},
+		},
+	}
+}
+
+// InviteTemplate is a shareable Flex card of the round with a button to join it.
+// The button joins through a postback in chats the bot is in; once the card is forwarded elsewhere,
+// the deep link opens the bot's chat with the invite number typed in. Without botBasicID the deep link is left out.
+func InviteTemplate(summary usecase.RoundSummary, botBasicID string) messaging_api.MessageInterface {
+	buttons := []messaging_api.FlexComponentInterface{
+		&messaging_api.FlexButton{
+			Style: messaging_api.FlexButtonSTYLE_PRIMARY,
+			Action: &messaging_api.PostbackAction{
+				Label:       "加入遊戲",
+				Data:        joinPostbackData(summary.InviteNo),
+				DisplayText: "加入遊戲",
 			},
 		},
 	}
+	if botBasicID != "" {
+		buttons = append(buttons, &messaging_api.FlexButton{
+			Style:  messaging_api.FlexButtonSTYLE_LINK,
+			Action: &messaging_api.UriAction{Label: "私訊機器人加入", Uri: inviteDeepLink(botBasicID, summary.InviteNo)},
+		})
+	}
+
+	return &messaging_api.FlexMessage{
+		AltText: "狼人殺房間 " + summary.InviteNo + "，點選加入遊戲",
+		Contents: &messaging_api.FlexBubble{
+			Body: &messaging_api.FlexBox{
+				Layout:  messaging_api.FlexBoxLAYOUT_VERTICAL,
+				Spacing: "sm",
+				Contents: []messaging_api.FlexComponentInterface{
+					&messaging_api.FlexText{Text: "狼人殺房間", Size: "sm", Color: "#888888"},
+					&messaging_api.FlexText{Text: summary.InviteNo, Size: "xxl", Weight: messaging_api.FlexTextWEIGHT_BOLD},
+					&messaging_api.FlexText{Text: domain.CompositionSummary(summary.Identities), Wrap: true},
+					&messaging_api.FlexText{Text: "目前 " + joinedCount(summary.Participants, len(summary.Identities)) + " 人", Size: "sm", Color: "#888888"},
+				},
+			},
+			Footer: &messaging_api.FlexBox{
+				Layout:   messaging_api.FlexBoxLAYOUT_VERTICAL,
+				Spacing:  "sm",
+				Contents: buttons,
+			},
+		},
+	}
+}
+
+// joinPostbackData encodes joining the round with the invite number as postback data.
+func joinPostbackData(inviteNo string) string {
+	return url.Values{"e": {EventJoin}, "i": {inviteNo}}.Encode()
+}
+
+// inviteDeepLink opens the bot's chat with the invite number typed in, ready to send.
+func inviteDeepLink(botBasicID, inviteNo string) string {
+	return "https://line.me/R/oaMessage/" + url.PathEscape(botBasicID) + "/?" + url.QueryEscape(inviteNo)
+}
+
+// maxQuickReplyItems is the LINE limit of quick reply buttons per message.
+const maxQuickReplyItems = 13
+
+// maxActionLabelLen is the LINE limit of characters in an action label.
+const maxActionLabelLen = 20
+
+// PromptTemplate renders a game prompt as a text message with a quick reply button per option.
+// Prompts with more options than LINE allows quick reply buttons, as on boards of 13 or more players,
+// are rendered as a Flex card with a button per option instead, so every target stays reachable.
+func PromptTemplate(ownerID string, prompt domain.Prompt) messaging_api.MessageInterface {
+	if len(prompt.Options) > maxQuickReplyItems {
+		return promptCardTemplate(ownerID, prompt)
+	}
+	var items []messaging_api.QuickReplyItem
+	for _, o := range prompt.Options {
+		items = append(items, messaging_api.QuickReplyItem{
+			Action: &messaging_api.PostbackAction{
+				Label:       truncateLabel(o.Label),
+				Data:        gamePostbackData(ownerID, prompt.Turn, o),
+				DisplayText: o.Label,
+			},
+		})
+	}
+	return &messaging_api.TextMessage{
+		Text:       prompt.Text,
+		QuickReply: &messaging_api.QuickReply{Items: items},
+	}
+}
+
+// promptCardTemplate renders a game prompt as a Flex card with a button per option.
+func promptCardTemplate(ownerID string, prompt domain.Prompt) messaging_api.MessageInterface {
+	buttons := make([]messaging_api.FlexComponentInterface, 0, len(prompt.Options))
+	for _, o := range prompt.Options {
+		buttons = append(buttons, &messaging_api.FlexButton{
+			Style:  messaging_api.FlexButtonSTYLE_SECONDARY,
+			Height: messaging_api.FlexButtonHEIGHT_SM,
+			Action: &messaging_api.PostbackAction{
+				Label:       truncateLabel(o.Label),
+				Data:        gamePostbackData(ownerID, prompt.Turn, o),
+				DisplayText: o.Label,
+			},
+		})
+	}
+	return &messaging_api.FlexMessage{
+		AltText: prompt.Text,
+		Contents: &messaging_api.FlexBubble{
+			Body: &messaging_api.FlexBox{
+				Layout: messaging_api.FlexBoxLAYOUT_VERTICAL,
+				Contents: []messaging_api.FlexComponentInterface{
+					&messaging_api.FlexText{Text: prompt.Text, Wrap: true},
+				},
+			},
+			Footer: &messaging_api.FlexBox{
+				Layout:   messaging_api.FlexBoxLAYOUT_VERTICAL,
+				Spacing:  "sm",
+				Contents: buttons,
+			},
+		},
+	}
+}
+
+// gamePostbackData encodes a game option of the prompt at the given turn as postback data.
+func gamePostbackData(ownerID string, turn int, o domain.Option) string {
+	return url.Values{
+		"e": {EventGame},
+		"r": {ownerID},
+		"n": {strconv.Itoa(turn)},
+		"a": {strconv.Itoa(int(o.Action))},
+		"t": {o.Target},
+	}.Encode()
+}
+
+// truncateLabel shortens s to the LINE action label limit.
+func truncateLabel(s string) string {
+	r := []rune(s)
+	if len(r) <= maxActionLabelLen {
+		return s
+	}
+	return string(r[:maxActionLabelLen])
+}
+
+// StartGameQuickReply offers the owner a button to start the game engine,
+// and one to open the room dashboard unless dashboardURL is empty.
+func StartGameQuickReply(dashboardURL string) *messaging_api.QuickReply {
+	items := []messaging_api.QuickReplyItem{
+		{Action: &messaging_api.PostbackAction{Label: "開始遊戲", Data: EventStart, DisplayText: "開始遊戲"}},
+	}
+	if dashboardURL != "" {
+		items = append(items, messaging_api.QuickReplyItem{
+			Action: &messaging_api.UriAction{Label: "房間管理", Uri: dashboardURL},
+		})
+	}
+	return &messaging_api.QuickReply{Items: items}
+}
+
+// roomDashboardURL opens the room dashboard LIFF page of the round, or is empty without a dashboard LIFF app.
+func roomDashboardURL(liffRoomID, inviteNo string) string {
+	if liffRoomID == "" {
+		return ""
+	}
+	return "https://liff.line.me/" + liffRoomID + "?" + url.Values{"i": {inviteNo}}.Encode()
+}
+
+// seatPickerURL opens the seat picker LIFF page of the round, served by the dashboard LIFF app, or is empty without it.
+func seatPickerURL(liffRoomID, inviteNo string) string {
+	if liffRoomID == "" {
+		return ""
+	}
+	return "https://liff.line.me/" + liffRoomID + "/seat?" + url.Values{"i": {inviteNo}}.Encode()
 }
@@ -0,0 +1,50 @@
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+	"werewolve-helper/internal/domain"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptTemplate_QuickReply(t *testing.T) {
+	prompt := domain.Prompt{UserID: "u1", Text: "請投票", Turn: 3, Options: []domain.Option{
+		{Label: "1號 U1", Action: domain.ActionVote, Target: "u1"},
+		{Label: "棄票", Action: domain.ActionSkip},
+	}}
+
+	msg, ok := PromptTemplate("owner1", prompt).(*messaging_api.TextMessage)
+	require.True(t, ok)
+	assert.Equal(t, "請投票", msg.Text)
+	require.Len(t, msg.QuickReply.Items, 2)
+
+	data, err := url.ParseQuery(msg.QuickReply.Items[0].Action.(*messaging_api.PostbackAction).Data)
+	require.NoError(t, err)
+	assert.Equal(t, "3", data.Get("n"), "The postback should carry the turn of the prompt")
+}
+
+func TestPromptTemplate_EighteenPlayers(t *testing.T) {
+	// An 18-player vote: every player plus abstaining, beyond the quick reply limit.
+	prompt := domain.Prompt{UserID: "u1", Text: "請投票"}
+	for seat := 1; seat <= domain.MaxPlayers; seat++ {
+		userID := fmt.Sprintf("u%d", seat)
+		prompt.Options = append(prompt.Options, domain.Option{Label: fmt.Sprintf("%d號 %s", seat, userID), Action: domain.ActionVote, Target: userID})
+	}
+	prompt.Options = append(prompt.Options, domain.Option{Label: "棄票", Action: domain.ActionSkip})
+
+	msg, ok := PromptTemplate("owner1", prompt).(*messaging_api.FlexMessage)
+	require.True(t, ok, "Too many options for quick replies should be a Flex card")
+	bubble := msg.Contents.(*messaging_api.FlexBubble)
+	require.Len(t, bubble.Footer.Contents, len(prompt.Options), "Every option should get a button")
+
+	var labels []string
+	for _, c := range bubble.Footer.Contents {
+		labels = append(labels, c.(*messaging_api.FlexButton).Action.(*messaging_api.PostbackAction).Label)
+	}
+	assert.Contains(t, labels, "18號 u18")
+	assert.Equal(t, "棄票", labels[len(labels)-1], "The abstain option should not be cut")
+}
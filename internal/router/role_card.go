@@ -0,0 +1,101 @@
+package router
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"werewolve-helper/internal/domain"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// roleArtBaseURL is where the images/ directory of the repository is published.
+const roleArtBaseURL = "https://raw.githubusercontent.com/islu/werewolve-helper/main/images/"
+
+// factionColors are the header colours of the role cards.
+var factionColors = map[domain.Faction]string{
+	domain.FactionWolf:       "#B03A2E",
+	domain.FactionGod:        "#2874A6",
+	domain.FactionVillager:   "#239B56",
+	domain.FactionThirdParty: "#7D3C98",
+}
+
+// RoleCardTemplate renders a participant's identity as a Flex card with the role art, the faction colour,
+// the seat, a short description of the ability and the teammates the participant knows.
+// Buttons open the seat picker if seatPickerURL is set, and leave the round if inviteNo is set.
+func RoleCardTemplate(p domain.Participant, teammates []domain.Participant, inviteNo, seatPickerURL string) messaging_api.MessageInterface {
+	role, _ := domain.LookupRole(p.Identity)
+	title := "你的身分"
+	if p.Seat > 0 {
+		title = strconv.Itoa(p.Seat) + "號・" + title
+	}
+
+	body := []messaging_api.FlexComponentInterface{
+		&messaging_api.FlexText{Text: role.Description, Wrap: true},
+	}
+	if len(teammates) > 0 {
+		names := make([]string, 0, len(teammates))
+		for _, t := range teammates {
+			names = append(names, t.Label())
+		}
+		body = append(body,
+			&messaging_api.FlexSeparator{Margin: "lg"},
+			&messaging_api.FlexText{Text: "狼隊友", Weight: messaging_api.FlexTextWEIGHT_BOLD, Margin: "lg"},
+			&messaging_api.FlexText{Text: strings.Join(names, "、"), Wrap: true},
+		)
+	}
+
+	bubble := &messaging_api.FlexBubble{
+		Header: &messaging_api.FlexBox{
+			Layout:          messaging_api.FlexBoxLAYOUT_VERTICAL,
+			BackgroundColor: factionColors[p.Identity.Faction()],
+			Contents: []messaging_api.FlexComponentInterface{
+				&messaging_api.FlexText{Text: title, Size: "sm", Color: "#FFFFFF"},
+				&messaging_api.FlexText{Text: role.Name, Size: "xxl", Weight: messaging_api.FlexTextWEIGHT_BOLD, Color: "#FFFFFF"},
+				&messaging_api.FlexText{Text: p.Identity.Faction().String(), Size: "sm", Color: "#FFFFFF"},
+			},
+		},
+		Body: &messaging_api.FlexBox{
+			Layout:   messaging_api.FlexBoxLAYOUT_VERTICAL,
+			Spacing:  "sm",
+			Contents: body,
+		},
+	}
+	var buttons []messaging_api.FlexComponentInterface
+	if seatPickerURL != "" {
+		buttons = append(buttons, &messaging_api.FlexButton{
+			Action: &messaging_api.UriAction{Label: "選擇座位", Uri: seatPickerURL},
+			Style:  messaging_api.FlexButtonSTYLE_LINK,
+		})
+	}
+	if inviteNo != "" {
+		buttons = append(buttons, &messaging_api.FlexButton{
+			Action: &messaging_api.PostbackAction{
+				Label:       "退出房間",
+				Data:        url.Values{"e": {EventLeave}, "i": {inviteNo}}.Encode(),
+				DisplayText: leaveCommand + " " + inviteNo,
+			},
+			Style: messaging_api.FlexButtonSTYLE_LINK,
+			Color: "#AAAAAA",
+		})
+	}
+	if len(buttons) > 0 {
+		bubble.Footer = &messaging_api.FlexBox{
+			Layout:   messaging_api.FlexBoxLAYOUT_VERTICAL,
+			Contents: buttons,
+		}
+	}
+	if role.Image != "" {
+		bubble.Hero = &messaging_api.FlexImage{
+			Url:         roleArtBaseURL + role.Image,
+			Size:        "full",
+			AspectRatio: "20:13",
+			AspectMode:  messaging_api.FlexImageASPECT_MODE_COVER,
+		}
+	}
+
+	return &messaging_api.FlexMessage{
+		AltText:  "你的身分是 " + role.Name,
+		Contents: bubble,
+	}
+}
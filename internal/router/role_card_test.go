@@ -0,0 +1,51 @@
+package router
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"werewolve-helper/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestRoleCardTemplate_Golden(t *testing.T) {
+	for _, role := range domain.Roles() {
+		name := strings.ReplaceAll(strings.ToLower(role.EnglishName), " ", "_")
+		t.Run(name, func(t *testing.T) {
+			p := domain.Participant{UserID: "u1", Name: "小明", Identity: role.Identity, Seat: 3}
+			var teammates []domain.Participant
+			if role.Faction == domain.FactionWolf {
+				teammates = []domain.Participant{
+					{UserID: "u2", Name: "阿狼", Identity: domain.Werewolf, Seat: 5},
+					{UserID: "u3", Name: "小紅", Identity: domain.WerewolfKing, Seat: 8},
+				}
+			}
+
+			got, err := json.MarshalIndent(RoleCardTemplate(p, teammates, "000001", "https://liff.line.me/room-liff/seat?i=000001"), "", "  ")
+			require.NoError(t, err)
+
+			golden := filepath.Join("testdata", "role_cards", name+".json")
+			if *update {
+				require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+				require.NoError(t, os.WriteFile(golden, append(got, '\n'), 0o600))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(want), string(got))
+		})
+	}
+}
+
+func TestRoleCardTemplate_GoodRolesHaveNoTeammates(t *testing.T) {
+	card, err := json.Marshal(RoleCardTemplate(domain.Participant{Name: "小明", Identity: domain.Seer}, nil, "", ""))
+	require.NoError(t, err)
+	assert.NotContains(t, string(card), "狼隊友")
+	assert.NotContains(t, string(card), "hero", "Roles without art should have no hero image")
+}
@@ -1,11 +1,22 @@
 package router
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 	"werewolve-helper/internal"
+	"werewolve-helper/internal/adapter/lineauth"
+	"werewolve-helper/internal/adapter/notify"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/usecase"
 
 	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
 
@@ -22,10 +33,63 @@ func StartServer() {
 		log.Fatalln(err)
 	}
 
+	if config.LineBotBasicID == "" {
+		// The basic ID builds the invite deep links; without it invites only offer the postback button
+		if info, err := bot.GetBotInfo(); err != nil {
+			log.Printf("get bot info error: %v", err)
+		} else {
+			config.LineBotBasicID = info.BasicId
+		}
+	}
+
+	repo, err := newRoundRepository(config)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	rm, err := usecase.NewRoundManager(repo)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	inviteFormat, err := usecase.LookupInviteFormat(config.InviteNoFormat, config.InviteNoLength)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	rm.SetInviteFormat(inviteFormat)
+	rm.Subscribe(notifyRoundEvent(config))
+	rm.Subscribe(revealWolfTeam(bot, rm))
+	rm.Subscribe(dealPlayingOwner(bot, rm, config.LiffRoomID))
+
+	templateRepo, err := newTemplateRepository(config)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	tm := usecase.NewTemplateManager(templateRepo, rm)
+	verifier := lineauth.NewIDTokenVerifier(config.LineLoginChannelID)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Evict expired rounds in the background
+	janitorDone := make(chan struct{})
+	go func() {
+		rm.RunJanitor(ctx, config.JanitorInterval)
+		close(janitorDone)
+	}()
+
 	// Register webhook
-	RegisterWebhook(config, bot)
+	limiter := NewJoinLimiter(defaultJoinLimits, time.Now)
+	RegisterWebhook(config, bot, rm, tm, limiter)
 	// Register LIFF page
 	RegisterLIFF(config)
+	// Register REST API
+	RegisterRoundAPI(verifier, bot, rm, config.LineBotBasicID, config.LiffRoomID)
+	RegisterRoomAPI(verifier, bot, rm, limiter)
+	RegisterTemplateAPI(verifier, tm)
+	RegisterBalanceAPI()
+	// Register join limiter metrics, only behind a token
+	if config.MetricsToken != "" {
+		http.Handle("GET /metrics/join", newJoinMetricsHandler(limiter, config.MetricsToken))
+	}
 	// Register health check
 	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -37,9 +101,44 @@ func StartServer() {
 		WriteTimeout: 10 * time.Second,
 	}
 
-	log.Println("Server starting on port " + config.Port)
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("ListenAndServe(): %v", err)
+	go func() {
+		log.Println("Server starting on port " + config.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("ListenAndServe(): %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Server shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Shutdown(): %v", err)
+	}
+	<-janitorDone
+
+	if closer, ok := repo.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("close round storage error: %v", err)
+		}
+	}
+	if closer, ok := templateRepo.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("close template storage error: %v", err)
+		}
+	}
+}
+
+// notifyRoundEvent forwards round lifecycle events to the Discord channel.
+func notifyRoundEvent(config internal.BotConfig) usecase.RoundEventHandler {
+	return func(e usecase.RoundEvent) {
+		msg := fmt.Sprintf("RoundEvent\n\n%s: owner=%s inviteNo=%s", e.Type, e.OwnerID, e.InviteNo)
+		go func() {
+			if _, err := notify.SendMessageByDiscord(config.DiscordBotToken, config.DiscordChannelID, msg); err != nil {
+				log.Println("Notify error: ", err)
+			}
+		}()
 	}
 }
 
@@ -47,6 +146,7 @@ func initBotConfig() internal.BotConfig {
 	channelSecret := mustGetenv("LINE_CHANNEL_SECRET")
 	channelToken := mustGetenv("LINE_CHANNEL_TOKEN")
 	liffID := mustGetenv("LIFF_ID")
+	liffRoomID := os.Getenv("LIFF_ROOM_ID")
 	dcBotToken := mustGetenv("DISCORD_BOT_TOKEN")
 	dcChannelID := mustGetenv("DISCORD_CHANNEL_ID")
 
@@ -55,14 +155,104 @@ func initBotConfig() internal.BotConfig {
 		port = "5000"
 	}
 
+	roundStorage := os.Getenv("ROUND_STORAGE")
+	if roundStorage == "" {
+		roundStorage = "memory"
+	}
+	roundStoragePath := os.Getenv("ROUND_STORAGE_PATH")
+	templateStoragePath := os.Getenv("TEMPLATE_STORAGE_PATH")
+	botBasicID := os.Getenv("LINE_BOT_BASIC_ID")
+
+	loginChannelID := os.Getenv("LINE_LOGIN_CHANNEL_ID")
+	if loginChannelID == "" {
+		loginChannelID = lineauth.ChannelIDFromLiffID(liffID)
+	}
+
+	inviteNoFormat := os.Getenv("INVITE_NO_FORMAT")
+	inviteNoLength := 0
+	if v := os.Getenv("INVITE_NO_LENGTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			log.Fatalf("Fatal Error: invalid INVITE_NO_LENGTH %q.\n", v)
+		}
+		inviteNoLength = n
+	}
+	if inviteNoFormat == "" && inviteNoLength > usecase.DefaultInviteNoLength {
+		// A longer length alone used to pick the alphanumeric invite numbers.
+		inviteNoFormat = usecase.Base32InviteFormat
+	}
+
+	metricsToken := os.Getenv("METRICS_TOKEN")
+
+	janitorInterval := 5 * time.Minute
+	if v := os.Getenv("ROUND_JANITOR_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("Fatal Error: invalid ROUND_JANITOR_INTERVAL %q.\n", v)
+		}
+		janitorInterval = d
+	}
+
 	return internal.BotConfig{
-		LineChannelSecret: channelSecret,
-		LineChannelToken:  channelToken,
-		Port:              port,
-		LiffID:            liffID,
-		DiscordBotToken:   dcBotToken,
-		DiscordChannelID:  dcChannelID,
+		LineChannelSecret:   channelSecret,
+		LineChannelToken:    channelToken,
+		Port:                port,
+		LiffID:              liffID,
+		LiffRoomID:          liffRoomID,
+		DiscordBotToken:     dcBotToken,
+		DiscordChannelID:    dcChannelID,
+		RoundStorage:        roundStorage,
+		RoundStoragePath:    roundStoragePath,
+		JanitorInterval:     janitorInterval,
+		TemplateStoragePath: templateStoragePath,
+		LineLoginChannelID:  loginChannelID,
+		LineBotBasicID:      botBasicID,
+		InviteNoFormat:      inviteNoFormat,
+		InviteNoLength:      inviteNoLength,
+		MetricsToken:        metricsToken,
+	}
+}
+
+// newRoundRepository picks the round storage backend configured by ROUND_STORAGE.
+func newRoundRepository(config internal.BotConfig) (usecase.RoundRepository, error) {
+	switch config.RoundStorage {
+	case "memory":
+		return storage.NewMemoryRoundRepository(), nil
+	case "file":
+		path := config.RoundStoragePath
+		if path == "" {
+			path = "rounds.json"
+		}
+		return storage.NewFileRoundRepository(path)
+	case "sqlite":
+		path := config.RoundStoragePath
+		if path == "" {
+			path = "rounds.db"
+		}
+		return storage.NewSQLiteRoundRepository(path)
+	}
+	return nil, errors.New("unknown ROUND_STORAGE " + config.RoundStorage)
+}
+
+// newTemplateRepository picks the template storage backend, which follows ROUND_STORAGE.
+func newTemplateRepository(config internal.BotConfig) (usecase.TemplateRepository, error) {
+	switch config.RoundStorage {
+	case "memory":
+		return storage.NewMemoryTemplateRepository(), nil
+	case "file":
+		path := config.TemplateStoragePath
+		if path == "" {
+			path = "templates.json"
+		}
+		return storage.NewFileTemplateRepository(path)
+	case "sqlite":
+		path := config.TemplateStoragePath
+		if path == "" {
+			path = "templates.db"
+		}
+		return storage.NewSQLiteTemplateRepository(path)
 	}
+	return nil, errors.New("unknown ROUND_STORAGE " + config.RoundStorage)
 }
 
 func mustGetenv(k string) string {
@@ -0,0 +1,50 @@
+package router
+
+import (
+	"log"
+	"strings"
+	"werewolve-helper/internal/domain"
+	"werewolve-helper/internal/usecase"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// revealWolfTeam pushes every wolf the teammates they may know once the round fills up,
+// as the wolves open their eyes together in a real game.
+func revealWolfTeam(bot *messaging_api.MessagingApiAPI, rm *usecase.RoundManager) usecase.RoundEventHandler {
+	return func(e usecase.RoundEvent) {
+		if e.Type != usecase.RoundFilled {
+			return
+		}
+		reveals, err := rm.TeamReveals(e.InviteNo)
+		if err != nil {
+			log.Printf("reveal wolf team of %s error: %v", e.InviteNo, err)
+			return
+		}
+		go func() {
+			for _, r := range reveals {
+				m1 := messaging_api.TextMessage{Text: wolfTeamMessage(r)}
+				if err := pushMessage(bot, r.Wolf.UserID, m1); err != nil {
+					log.Printf("push wolf team to %s error: %v", r.Wolf.UserID, err)
+				}
+			}
+		}()
+	}
+}
+
+// wolfTeamMessage lists the teammates of a wolf by seat, display name and role.
+func wolfTeamMessage(r domain.TeamReveal) string {
+	if len(r.Teammates) == 0 {
+		return "房間已額滿，沒有你認識的狼隊友，請獨自行動"
+	}
+	var sb strings.Builder
+	sb.WriteString("房間已額滿，你的狼隊友是:")
+	for _, p := range r.Teammates {
+		sb.WriteString("\n・")
+		sb.WriteString(p.Label())
+		sb.WriteString(" (")
+		sb.WriteString(p.Identity.String())
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
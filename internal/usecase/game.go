@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"werewolve-helper/internal/domain"
+)
+
+// Errors returned by game actions.
+var (
+	ErrGameNotStarted = errors.New("game not started")
+	ErrPromptExpired  = errors.New("prompt expired")
+)
+
+// GameUpdate carries everything the router must deliver after a game change.
+type GameUpdate struct {
+	Audience []string        // Owner and every player, the recipients of public notices.
+	GroupID  string          // Chat the round is played in; when set, public notices go there instead of the audience.
+	Notices  []domain.Notice // Announcements and private results.
+	Prompts  []domain.Prompt // Prompts of a new turn; empty when the turn did not change.
+}
+
+// StartGame starts the game engine of the round with the given invite number, moderated by the user.
+func (m *RoundManager) StartGame(inviteNo, userID string) (GameUpdate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, userID, domain.PermModerate)
+	if err != nil {
+		return GameUpdate{}, err
+	}
+	if err := r.StartGame(userID); err != nil {
+		return GameUpdate{}, err
+	}
+	m.saveLocked(r)
+
+	return GameUpdate{
+		Audience: audience(r),
+		GroupID:  r.GroupID,
+		Notices:  []domain.Notice{{Text: "遊戲開始，天黑請閉眼"}},
+		Prompts:  r.Game.Prompts(),
+	}, nil
+}
+
+// Act performs a game action in the owner's round on behalf of actorID, answering the prompt of the given turn.
+// It returns ErrPromptExpired if the game has moved on since, so that old buttons cannot act on a later turn.
+func (m *RoundManager) Act(ownerID, actorID string, turn int, action domain.Action, targetID string) (GameUpdate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.rounds[ownerID]
+	if !ok {
+		return GameUpdate{}, ErrRoundNotFound
+	}
+	if r.Game == nil {
+		return GameUpdate{}, ErrGameNotStarted
+	}
+	if action == domain.ActionStartVote {
+		if _, err := m.authorizeLocked(r.InviteNo, actorID, domain.PermModerate); err != nil {
+			return GameUpdate{}, err
+		}
+	}
+
+	if turn != r.Game.Turn && r.Game.Phase != domain.PhaseEnded {
+		return GameUpdate{}, ErrPromptExpired
+	}
+	notices, err := r.Game.Act(actorID, action, targetID)
+	if err != nil {
+		return GameUpdate{}, err
+	}
+	m.saveLocked(r)
+
+	update := GameUpdate{Audience: audience(r), GroupID: r.GroupID, Notices: notices}
+	if r.Game.Turn != turn {
+		update.Prompts = r.Game.Prompts()
+	}
+	return update, nil
+}
+
+// audience returns the owner, the co-hosts and every participant of the round.
+func audience(r *domain.Round) []string {
+	ids := []string{r.OwnerID}
+	for _, c := range r.CoHosts {
+		ids = append(ids, c.UserID)
+	}
+	for _, p := range r.Participants {
+		if !slices.Contains(ids, p.UserID) {
+			ids = append(ids, p.UserID)
+		}
+	}
+	return ids
+}
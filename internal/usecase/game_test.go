@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"werewolve-helper/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundManager_Game(t *testing.T) {
+	m := newTestManager(t)
+	r := domain.NewRound("owner1", "000001")
+	r.SetIdentity("owner1", domain.Werewolf, 1)
+	r.SetIdentity("owner1", domain.Villager, 1)
+	require.NoError(t, m.Create(r))
+	assert := assert.New(t)
+
+	_, err := m.Act("owner1", "user1", 0, domain.ActionWolfKill, "user2")
+	assert.ErrorIs(err, ErrGameNotStarted)
+	_, err = m.StartGame("000001", "owner1")
+	assert.ErrorIs(err, domain.ErrRegistrationOpen)
+
+	wolf := mustJoin(t, m, "000001", "user1", "User One", "url1")
+	villager := mustJoin(t, m, "000001", "user2", "User Two", "url2")
+	if wolf.Identity != domain.Werewolf {
+		wolf, villager = villager, wolf
+	}
+
+	update, err := m.StartGame("000001", "owner1")
+	require.NoError(t, err)
+	assert.ElementsMatch([]string{"owner1", "user1", "user2"}, update.Audience)
+	require.Len(t, update.Prompts, 1)
+	assert.Equal(wolf.UserID, update.Prompts[0].UserID, "The wolf should be prompted first")
+
+	_, err = m.Act("owner1", villager.UserID, update.Prompts[0].Turn, domain.ActionWolfKill, wolf.UserID)
+	assert.ErrorIs(err, domain.ErrNotYourTurn)
+
+	update, err = m.Act("owner1", wolf.UserID, update.Prompts[0].Turn, domain.ActionWolfKill, villager.UserID)
+	require.NoError(t, err)
+	require.Len(t, update.Notices, 2)
+	assert.Equal("天亮了，昨晚 "+villager.Label()+" 死亡", update.Notices[0].Text)
+	assert.Contains(update.Notices[1].Text, "遊戲結束，狼人陣營獲勝", "The last good player's death ends the game")
+	assert.Empty(update.Prompts)
+
+	_, err = m.Act("owner1", "owner1", 0, domain.ActionStartVote, "")
+	assert.ErrorIs(err, domain.ErrGameEnded)
+}
+
+func TestRoundManager_ActRejectsExpiredPrompt(t *testing.T) {
+	m := newTestManager(t)
+	r := domain.NewRound("owner1", "000001")
+	r.SetIdentity("owner1", domain.Werewolf, 1)
+	r.SetIdentity("owner1", domain.Villager, 3)
+	require.NoError(t, m.Create(r))
+	for i := 1; i <= 4; i++ {
+		mustJoin(t, m, "000001", fmt.Sprintf("user%d", i), fmt.Sprintf("User %d", i), "")
+	}
+
+	update, err := m.StartGame("000001", "owner1")
+	require.NoError(t, err)
+	require.Len(t, update.Prompts, 1)
+	night := update.Prompts[0]
+	kill := killOption(t, night)
+
+	update, err = m.Act("owner1", night.UserID, night.Turn, kill.Action, kill.Target)
+	require.NoError(t, err)
+	require.Len(t, update.Prompts, 1, "The day should begin with the moderator prompt")
+	assert.Greater(t, update.Prompts[0].Turn, night.Turn)
+
+	// Replaying the button of the night prompt must not act on the day.
+	_, err = m.Act("owner1", night.UserID, night.Turn, kill.Action, kill.Target)
+	assert.ErrorIs(t, err, ErrPromptExpired)
+	_, err = m.Act("owner1", "owner1", night.Turn, domain.ActionStartVote, "")
+	assert.ErrorIs(t, err, ErrPromptExpired)
+
+	_, err = m.Act("owner1", "owner1", update.Prompts[0].Turn, domain.ActionStartVote, "")
+	assert.NoError(t, err)
+}
+
+func TestRoundManager_CoHostStartsVote(t *testing.T) {
+	m := newTestManager(t)
+	r := domain.NewRound("owner1", "000001")
+	r.SetIdentity("owner1", domain.Werewolf, 1)
+	r.SetIdentity("owner1", domain.Villager, 3)
+	require.NoError(t, m.Create(r))
+	for i := 1; i <= 4; i++ {
+		mustJoin(t, m, "000001", fmt.Sprintf("user%d", i), fmt.Sprintf("User %d", i), "")
+	}
+	require.NoError(t, m.GrantCoHost("000001", "owner1", "host1", "Host"))
+	require.NoError(t, m.GrantCoHost("000001", "owner1", "host2", "Host"))
+	assert := assert.New(t)
+
+	update, err := m.StartGame("000001", "owner1")
+	require.NoError(t, err)
+	night := update.Prompts[0]
+	kill := killOption(t, night)
+	update, err = m.Act("owner1", night.UserID, night.Turn, kill.Action, kill.Target)
+	require.NoError(t, err)
+	day := update.Prompts[0]
+
+	_, err = m.Act("owner1", "user1", day.Turn, domain.ActionStartVote, "")
+	assert.ErrorIs(err, domain.ErrNotPermitted, "Players cannot open the vote")
+	require.NoError(t, m.RevokeCoHost("000001", "owner1", "host2"))
+	_, err = m.Act("owner1", "host2", day.Turn, domain.ActionStartVote, "")
+	assert.ErrorIs(err, ErrRoundNotFound, "A revoked co-host no longer moderates")
+
+	update, err = m.Act("owner1", "host1", day.Turn, domain.ActionStartVote, "")
+	require.NoError(t, err, "A co-host moderates the day")
+	assert.Equal("開始投票", update.Notices[0].Text)
+	assert.NotEmpty(update.Prompts)
+}
+
+// killOption returns the option of the wolf's prompt that kills someone else, so the game goes on to the day.
+func killOption(t *testing.T, prompt domain.Prompt) domain.Option {
+	t.Helper()
+	for _, o := range prompt.Options {
+		if o.Action == domain.ActionWolfKill && o.Target != "" && o.Target != prompt.UserID {
+			return o
+		}
+	}
+	require.FailNow(t, "no kill option", "%+v", prompt)
+	return domain.Option{}
+}
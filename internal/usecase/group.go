@@ -0,0 +1,58 @@
+package usecase
+
+// BindGroup binds the owner's round to a group or multi-person chat, where its public announcements are sent.
+// A round previously bound to the chat is unbound, and so is the chat the owner's round was bound to before.
+func (m *RoundManager) BindGroup(ownerID, groupID string) (RoundSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.rounds[ownerID]
+	if !ok {
+		return RoundSummary{}, ErrRoundNotFound
+	}
+	m.unbindLocked(groupID)
+	if r.GroupID != "" {
+		delete(m.groups, r.GroupID)
+	}
+	r.GroupID = groupID
+	m.groups[groupID] = ownerID
+	m.saveLocked(r)
+	return roundSummary(r), nil
+}
+
+// UnbindGroup detaches the round bound to the chat, if any, e.g. when the bot leaves the chat.
+func (m *RoundManager) UnbindGroup(groupID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.unbindLocked(groupID)
+}
+
+// FindByGroup returns the round bound to the chat.
+func (m *RoundManager) FindByGroup(groupID string) (RoundSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ownerID, ok := m.groups[groupID]
+	if !ok {
+		return RoundSummary{}, ErrGroupNotBound
+	}
+	r, ok := m.rounds[ownerID]
+	if !ok {
+		return RoundSummary{}, ErrGroupNotBound
+	}
+	return roundSummary(r), nil
+}
+
+// unbindLocked detaches the round bound to the chat. The caller must hold m.mu.
+func (m *RoundManager) unbindLocked(groupID string) {
+	ownerID, ok := m.groups[groupID]
+	if !ok {
+		return
+	}
+	delete(m.groups, groupID)
+	if r, ok := m.rounds[ownerID]; ok {
+		r.GroupID = ""
+		m.saveLocked(r)
+	}
+}
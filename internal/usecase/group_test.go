@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"testing"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundManager_BindGroup(t *testing.T) {
+	m := newTestManager(t)
+	assert := assert.New(t)
+
+	_, err := m.BindGroup("owner1", "group1")
+	assert.ErrorIs(err, ErrRoundNotFound)
+
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	gr, err := m.BindGroup("owner1", "group1")
+	require.NoError(t, err)
+	assert.Equal("000001", gr.InviteNo)
+	assert.Len(gr.Identities, 2)
+	assert.Zero(gr.Participants)
+
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+	gr, err = m.FindByGroup("group1")
+	require.NoError(t, err)
+	assert.Equal("owner1", gr.OwnerID)
+	assert.Equal(1, gr.Participants)
+
+	// Another owner's round takes over the group.
+	require.NoError(t, m.Create(newTestRound("owner2", "000002", 1)))
+	_, err = m.BindGroup("owner2", "group1")
+	require.NoError(t, err)
+	gr, err = m.FindByGroup("group1")
+	require.NoError(t, err)
+	assert.Equal("owner2", gr.OwnerID)
+
+	// Moving a round to another group releases the first one.
+	_, err = m.BindGroup("owner2", "group2")
+	require.NoError(t, err)
+	_, err = m.FindByGroup("group1")
+	assert.ErrorIs(err, ErrGroupNotBound)
+
+	m.UnbindGroup("group2")
+	_, err = m.FindByGroup("group2")
+	assert.ErrorIs(err, ErrGroupNotBound)
+}
+
+func TestRoundManager_GroupReleasedWithRound(t *testing.T) {
+	repo := storage.NewMemoryRoundRepository()
+	m, err := NewRoundManager(repo)
+	require.NoError(t, err)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 1)))
+	_, err = m.BindGroup("owner1", "group1")
+	require.NoError(t, err)
+
+	// The binding is stored with the round and indexed again on start.
+	reloaded, err := NewRoundManager(repo)
+	require.NoError(t, err)
+	gr, err := reloaded.FindByGroup("group1")
+	require.NoError(t, err)
+	assert.Equal(t, "000001", gr.InviteNo)
+
+	m.Expire("owner1")
+	_, err = m.FindByGroup("group1")
+	assert.ErrorIs(t, err, ErrGroupNotBound)
+}
+
+func TestRoundManager_GameInGroup(t *testing.T) {
+	m := newTestManager(t)
+	r := domain.NewRound("owner1", "000001")
+	r.SetIdentity("owner1", domain.Werewolf, 1)
+	r.SetIdentity("owner1", domain.Villager, 1)
+	require.NoError(t, m.Create(r))
+	_, err := m.BindGroup("owner1", "group1")
+	require.NoError(t, err)
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+	require.Equal(t, JoinJoined, m.Join("000001", "user2", "User Two", "url2").Status)
+
+	update, err := m.StartGame("000001", "owner1")
+	require.NoError(t, err)
+	assert.Equal(t, "group1", update.GroupID, "Public notices should go to the bound group")
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"slices"
+	"time"
+	"werewolve-helper/internal/domain"
+)
+
+// HostedInviteNo returns the invite number of the round the user owns or, failing that, co-hosts.
+func (m *RoundManager) HostedInviteNo(userID string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.hostedLocked(userID)
+	if !ok {
+		return "", ErrRoundNotFound
+	}
+	return r.InviteNo, nil
+}
+
+// GrantCoHost makes the user a co-host of the round with the given invite number on behalf of the owner.
+// A user may co-host several rounds: the latest grant wins when they ask the bot about "their" round,
+// and the others stay reachable by invite number.
+func (m *RoundManager) GrantCoHost(inviteNo, ownerID, userID, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, ownerID, domain.PermGrant)
+	if err != nil {
+		return err
+	}
+	if err := r.GrantCoHost(ownerID, userID, name); err != nil {
+		return err
+	}
+	if !slices.Contains(m.cohosts[userID], r.OwnerID) {
+		m.cohosts[userID] = append(m.cohosts[userID], r.OwnerID)
+	}
+	m.saveLocked(r)
+	return nil
+}
+
+// RevokeCoHost takes the co-host rights on the round with the given invite number back from the user.
+func (m *RoundManager) RevokeCoHost(inviteNo, ownerID, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, ownerID, domain.PermGrant)
+	if err != nil {
+		return err
+	}
+	if err := r.RevokeCoHost(ownerID, userID); err != nil {
+		return err
+	}
+	m.unindexCoHostLocked(userID, r.OwnerID)
+	m.saveLocked(r)
+	return nil
+}
+
+// SetOwnerPlays chooses whether the owner of the round with the given invite number plays instead of moderating.
+func (m *RoundManager) SetOwnerPlays(inviteNo, ownerID string, plays bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, ownerID, domain.PermGrant)
+	if err != nil {
+		return err
+	}
+	if err := r.SetOwnerPlays(ownerID, plays); err != nil {
+		return err
+	}
+	m.saveLocked(r)
+	return nil
+}
+
+// PlayAsOwner makes the owner of the round with the given invite number play in it, dealing them an identity.
+// See domain.Round.PlayAsOwner.
+func (m *RoundManager) PlayAsOwner(inviteNo, ownerID, name, pictureURL string) (domain.Participant, error) {
+	m.mu.Lock()
+	defer m.publishPending()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, ownerID, domain.PermGrant)
+	if err != nil {
+		return domain.Participant{}, err
+	}
+	wasFull := r.IsRegistrationClose()
+	p, err := r.PlayAsOwner(name, pictureURL)
+	if err != nil {
+		return domain.Participant{}, err
+	}
+	m.saveLocked(r)
+	if !wasFull && r.IsRegistrationClose() {
+		m.emitLocked(RoundFilled, r)
+	}
+	return p, nil
+}
+
+// PlayingOwner returns the owner of the round with the given invite number as a participant,
+// or false unless the owner plays and has been dealt an identity.
+func (m *RoundManager) PlayingOwner(inviteNo string) (domain.Participant, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.findByInviteNoLocked(inviteNo)
+	if !ok || !r.OwnerPlays {
+		return domain.Participant{}, false
+	}
+	for _, p := range r.Participants {
+		if p.UserID == r.OwnerID {
+			return p, true
+		}
+	}
+	return domain.Participant{}, false
+}
+
+// hostedLocked returns the round the user owns or, failing that, the one they were granted to co-host last.
+// The caller must hold m.mu.
+func (m *RoundManager) hostedLocked(userID string) (*domain.Round, bool) {
+	if r, ok := m.rounds[userID]; ok {
+		return r, true
+	}
+	owners := m.cohosts[userID]
+	for i := len(owners) - 1; i >= 0; i-- {
+		if r, ok := m.rounds[owners[i]]; ok && r.IsCoHost(userID) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// unindexCoHostLocked forgets that the user co-hosts the owner's round. The caller must hold m.mu.
+func (m *RoundManager) unindexCoHostLocked(userID, ownerID string) {
+	owners := slices.DeleteFunc(m.cohosts[userID], func(id string) bool { return id == ownerID })
+	if len(owners) == 0 {
+		delete(m.cohosts, userID)
+		return
+	}
+	m.cohosts[userID] = owners
+}
+
+// coHostGrantedAt returns when the user was granted to co-host the round, or the zero time if they do not.
+func coHostGrantedAt(r *domain.Round, userID string) time.Time {
+	for _, c := range r.CoHosts {
+		if c.UserID == userID {
+			return c.GrantedAt
+		}
+	}
+	return time.Time{}
+}
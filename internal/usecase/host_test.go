@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"testing"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundManager_CoHost(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	mustJoin(t, m, "000001", "user1", "User One", "")
+	assert := assert.New(t)
+
+	_, err := m.HostedInviteNo("host1")
+	require.ErrorIs(t, err, ErrRoundNotFound)
+	assert.ErrorIs(m.GrantCoHost("000001", "user1", "host1", "Host"), domain.ErrNotPermitted)
+	require.NoError(t, m.GrantCoHost("000001", "owner1", "host1", "Host"))
+
+	inviteNo, err := m.HostedInviteNo("host1")
+	require.NoError(t, err)
+	assert.Equal("000001", inviteNo)
+	_, info, err := m.Look("host1")
+	require.NoError(t, err)
+	assert.Contains(info, "User One")
+
+	room, err := m.Room("000001", "host1")
+	require.NoError(t, err)
+	assert.True(room.RolesVisible)
+	assert.True(room.CanModerate)
+	assert.False(room.CanManage)
+	assert.ErrorIs(m.Kick("000001", "host1", "user1"), domain.ErrNotPermitted)
+	assert.ErrorIs(m.Close("000001", "host1"), domain.ErrNotPermitted)
+
+	mustJoin(t, m, "000001", "user2", "User Two", "")
+	update, err := m.StartGame("000001", "host1")
+	require.NoError(t, err)
+	assert.Contains(update.Audience, "host1", "Co-hosts should hear the public notices")
+	require.NoError(t, m.Again("000001", "host1"))
+
+	require.NoError(t, m.RevokeCoHost("000001", "owner1", "host1"))
+	_, err = m.HostedInviteNo("host1")
+	require.ErrorIs(t, err, ErrRoundNotFound)
+	_, err = m.Room("000001", "host1")
+	assert.ErrorIs(err, ErrRoundNotFound, "A revoked co-host no longer learns the round exists")
+}
+
+func TestRoundManager_CoHostReloaded(t *testing.T) {
+	repo := storage.NewMemoryRoundRepository()
+	m, err := NewRoundManager(repo)
+	require.NoError(t, err)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	require.NoError(t, m.GrantCoHost("000001", "owner1", "host1", "Host"))
+
+	m, err = NewRoundManager(repo)
+	require.NoError(t, err)
+	inviteNo, err := m.HostedInviteNo("host1")
+	require.NoError(t, err)
+	assert.Equal(t, "000001", inviteNo)
+
+	m.Expire("owner1")
+	_, err = m.HostedInviteNo("host1")
+	assert.ErrorIs(t, err, ErrRoundNotFound)
+}
+
+func TestRoundManager_CoHostsSeveralRounds(t *testing.T) {
+	repo := storage.NewMemoryRoundRepository()
+	m, err := NewRoundManager(repo)
+	require.NoError(t, err)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	require.NoError(t, m.Create(newTestRound("owner2", "000002", 2)))
+	require.NoError(t, m.Create(newTestRound("owner3", "000003", 2)))
+	require.NoError(t, m.GrantCoHost("000003", "owner3", "host1", "Host"))
+	require.NoError(t, m.GrantCoHost("000001", "owner1", "host1", "Host"))
+	require.NoError(t, m.GrantCoHost("000002", "owner2", "host1", "Host"))
+	assert := assert.New(t)
+
+	inviteNo, err := m.HostedInviteNo("host1")
+	require.NoError(t, err)
+	assert.Equal("000002", inviteNo, "The latest grant wins")
+
+	// The order of the grants survives a restart, whatever order the rounds are loaded in.
+	for range 10 {
+		m, err = NewRoundManager(repo)
+		require.NoError(t, err)
+		inviteNo, err = m.HostedInviteNo("host1")
+		require.NoError(t, err)
+		assert.Equal("000002", inviteNo)
+	}
+
+	// Losing one round keeps the others.
+	require.NoError(t, m.RevokeCoHost("000002", "owner2", "host1"))
+	inviteNo, err = m.HostedInviteNo("host1")
+	require.NoError(t, err)
+	assert.Equal("000001", inviteNo)
+	_, err = m.Room("000003", "host1")
+	require.NoError(t, err, "Still a co-host of the other rounds")
+
+	m.Expire("owner1")
+	inviteNo, err = m.HostedInviteNo("host1")
+	require.NoError(t, err)
+	assert.Equal("000003", inviteNo)
+
+	m.Expire("owner3")
+	_, err = m.HostedInviteNo("host1")
+	assert.ErrorIs(err, ErrRoundNotFound)
+}
+
+func TestRoundManager_OwnerPlays(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	var got []RoundEventType
+	m.Subscribe(func(e RoundEvent) { got = append(got, e.Type) })
+	mustJoin(t, m, "000001", "user1", "User One", "")
+	assert := assert.New(t)
+
+	_, err := m.PlayAsOwner("000001", "user1", "User One", "")
+	require.ErrorIs(t, err, domain.ErrNotPermitted)
+	p, err := m.PlayAsOwner("000001", "owner1", "Owner", "")
+	require.NoError(t, err)
+	assert.Equal(2, p.Seat)
+	assert.Equal([]RoundEventType{RoundFilled}, got, "The owner filling the round should reveal the wolves")
+
+	_, info, err := m.Look("owner1")
+	require.NoError(t, err)
+	assert.Contains(info, "2號 Owner:已加入")
+	assert.NotContains(info, "平民")
+
+	room, err := m.Room("000001", "owner1")
+	require.NoError(t, err)
+	assert.False(room.RolesVisible)
+	assert.True(room.CanManage)
+	for _, p := range room.Participants {
+		assert.Zero(p.Identity, "A playing owner should not see the identities")
+	}
+	_, err = m.StartGame("000001", "owner1")
+	assert.ErrorIs(err, domain.ErrNotPermitted)
+}
+
+func TestRoundManager_AgainDealsPlayingOwner(t *testing.T) {
+	m := newTestManager(t)
+	round := domain.NewRound("owner1", "000001")
+	round.SetIdentity("owner1", domain.Werewolf, 1)
+	round.SetIdentity("owner1", domain.Villager, 2)
+	require.NoError(t, m.Create(round))
+	_, err := m.PlayAsOwner("000001", "owner1", "Owner", "url")
+	require.NoError(t, err)
+	mustJoin(t, m, "000001", "user1", "User One", "")
+	var got []RoundEventType
+	m.Subscribe(func(e RoundEvent) { got = append(got, e.Type) })
+	assert := assert.New(t)
+
+	require.NoError(t, m.Again("000001", "owner1"))
+	assert.Equal([]RoundEventType{RoundReshuffled}, got)
+
+	owner, ok := m.PlayingOwner("000001")
+	require.True(t, ok, "A playing owner should be dealt again on reshuffle")
+	assert.Equal("Owner", owner.Name)
+	assert.Equal("url", owner.PictureURL)
+	assert.Equal(1, owner.Seat)
+	_, info, err := m.Look("owner1")
+	require.NoError(t, err)
+	assert.Contains(info, "目前參與人數: 1/3")
+	assert.Contains(info, "1號 Owner:已加入")
+
+	res := m.Join("000001", "owner1", "Owner", "url")
+	assert.Equal(JoinAlreadyJoined, res.Status, "The owner keeps the identity they were dealt")
+}
+
+func TestRoundManager_PlayingOwner(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+
+	_, ok := m.PlayingOwner("000001")
+	assert.False(t, ok, "A moderating owner is not dealt")
+	_, ok = m.PlayingOwner("999999")
+	assert.False(t, ok)
+
+	require.NoError(t, m.Again("000001", "owner1"))
+	_, ok = m.PlayingOwner("000001")
+	assert.False(t, ok, "A moderating owner is not dealt on reshuffle")
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"werewolve-helper/internal/domain"
+)
+
+// Constants for the invite number formats.
+const (
+	DigitsInviteFormat = "digits" // Six digits, easy to type but easy to guess.
+	WordsInviteFormat  = "words"  // Two words and two digits, e.g. "wolf-moon-42".
+	Base32InviteFormat = "base32" // Short letters and digits without lookalikes, hard to guess.
+
+	DefaultInviteNoLength       = 6  // Length of digit invite numbers.
+	DefaultBase32InviteNoLength = 8  // Length of base32 invite numbers unless configured.
+	MaxInviteNoLength           = 16 // Longest base32 invite number.
+
+	// base32Alphabet spells the base32 invite numbers, without the lookalikes 0/O and 1/I.
+	base32Alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// inviteWords spell the word-based invite numbers.
+var inviteWords = []string{
+	"wolf", "moon", "night", "seer", "witch", "hunter", "guard", "village",
+	"howl", "shadow", "forest", "owl", "raven", "ember", "frost", "storm",
+	"star", "river", "stone", "fang", "claw", "mist", "dawn", "dusk",
+	"lantern", "cloak", "silver", "ash", "oak", "pine", "hill", "bell",
+}
+
+// Errors returned by invite number operations.
+var (
+	ErrInviteFormat      = errors.New("unknown invite number format")
+	ErrInviteNoLength    = errors.New("invalid invite number length")
+	ErrInviteNoExhausted = errors.New("no unused invite number found")
+)
+
+// InviteFormat draws invite numbers of one shape and recognizes them in chat text.
+type InviteFormat interface {
+	// Name returns the configuration name of the format.
+	Name() string
+	// Draw returns a random invite number.
+	Draw() (string, error)
+	// Normalize returns text as an invite number of this format, or false if it is not one.
+	Normalize(text string) (string, bool)
+}
+
+// LookupInviteFormat returns the named invite number format.
+// length only applies to the base32 format; zero picks its default.
+func LookupInviteFormat(name string, length int) (InviteFormat, error) {
+	switch name {
+	case "", DigitsInviteFormat:
+		return DigitsFormat{}, nil
+	case WordsInviteFormat:
+		return WordsFormat{}, nil
+	case Base32InviteFormat:
+		if length == 0 {
+			length = DefaultBase32InviteNoLength
+		}
+		if length <= DefaultInviteNoLength || length > MaxInviteNoLength {
+			return nil, fmt.Errorf("%w: %d", ErrInviteNoLength, length)
+		}
+		return Base32Format{Length: length}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInviteFormat, name)
+}
+
+// NormalizeInviteNo returns text as an invite number of any format, or false if it is not shaped like one.
+// Word and base32 invite numbers may be typed in any case. Since ordinary words pass for base32 numbers,
+// text typed by users should go through RoundManager.ParseInviteNo instead.
+func NormalizeInviteNo(text string) (string, bool) {
+	if inviteNo, ok := (DigitsFormat{}).Normalize(text); ok {
+		return inviteNo, true
+	}
+	if inviteNo, ok := (WordsFormat{}).Normalize(text); ok {
+		return inviteNo, true
+	}
+	if n := len(text); n > DefaultInviteNoLength && n <= MaxInviteNoLength {
+		return Base32Format{Length: n}.Normalize(text)
+	}
+	return "", false
+}
+
+// DigitsFormat draws six-digit invite numbers, from 000000 to 999999.
+type DigitsFormat struct{}
+
+// Name implements InviteFormat.
+func (DigitsFormat) Name() string { return DigitsInviteFormat }
+
+// Draw implements InviteFormat.
+func (DigitsFormat) Draw() (string, error) {
+	n, err := domain.Rng.IntN(1_000_000)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n), nil
+}
+
+// Normalize implements InviteFormat.
+func (DigitsFormat) Normalize(text string) (string, bool) {
+	if len(text) != DefaultInviteNoLength {
+		return "", false
+	}
+	for _, c := range text {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	return text, true
+}
+
+// WordsFormat draws invite numbers made of two words and two digits, e.g. "wolf-moon-42".
+type WordsFormat struct{}
+
+// Name implements InviteFormat.
+func (WordsFormat) Name() string { return WordsInviteFormat }
+
+// Draw implements InviteFormat.
+func (WordsFormat) Draw() (string, error) {
+	first, err := domain.Rng.IntN(len(inviteWords))
+	if err != nil {
+		return "", err
+	}
+	second, err := domain.Rng.IntN(len(inviteWords))
+	if err != nil {
+		return "", err
+	}
+	n, err := domain.Rng.IntN(100)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s-%02d", inviteWords[first], inviteWords[second], n), nil
+}
+
+// Normalize implements InviteFormat.
+func (WordsFormat) Normalize(text string) (string, bool) {
+	parts := strings.Split(strings.ToLower(text), "-")
+	if len(parts) != 3 || !isInviteWord(parts[0]) || !isInviteWord(parts[1]) || len(parts[2]) != 2 {
+		return "", false
+	}
+	for _, c := range parts[2] {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	return strings.Join(parts, "-"), true
+}
+
+// isInviteWord reports whether w is one of the words spelling invite numbers.
+func isInviteWord(w string) bool {
+	for _, word := range inviteWords {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+// Base32Format draws invite numbers of Length letters and digits, without the lookalikes 0/O and 1/I.
+type Base32Format struct {
+	Length int
+}
+
+// Name implements InviteFormat.
+func (Base32Format) Name() string { return Base32InviteFormat }
+
+// Draw implements InviteFormat.
+func (f Base32Format) Draw() (string, error) {
+	var sb strings.Builder
+	for range f.Length {
+		i, err := domain.Rng.IntN(len(base32Alphabet))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(base32Alphabet[i])
+	}
+	return sb.String(), nil
+}
+
+// Normalize implements InviteFormat.
+func (f Base32Format) Normalize(text string) (string, bool) {
+	if len(text) != f.Length {
+		return "", false
+	}
+	text = strings.ToUpper(text)
+	for _, c := range text {
+		if !strings.ContainsRune(base32Alphabet, c) {
+			return "", false
+		}
+	}
+	return text, true
+}
+
+// SetInviteFormat sets the format of the invite numbers drawn by NewInviteNo.
+// Rounds already created keep their invite numbers.
+func (m *RoundManager) SetInviteFormat(format InviteFormat) {
+	m.codes.SetFormat(format)
+}
+
+// ParseInviteNo returns text as an invite number if it is shaped like the numbers new rounds get.
+// Numbers of other formats are only recognized when a round uses them, so rounds created before a format change
+// stay reachable while ordinary words typed in chat are not taken for invite numbers.
+func (m *RoundManager) ParseInviteNo(text string) (string, bool) {
+	if inviteNo, ok := m.codes.Format().Normalize(text); ok {
+		return inviteNo, true
+	}
+	if inviteNo, ok := NormalizeInviteNo(text); ok && m.HasInviteNo(inviteNo) {
+		return inviteNo, true
+	}
+	return "", false
+}
+
+// NewInviteNo reserves a random invite number no round uses yet.
+// The reservation holds until a round is created with it, or lapses after a few minutes.
+func (m *RoundManager) NewInviteNo() (string, error) {
+	return m.codes.Allocate()
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+// Constants for the invite number allocator.
+const (
+	// inviteReservationTTL is how long an allocated invite number stays reserved without a round using it.
+	inviteReservationTTL = 5 * time.Minute
+	// inviteRecycleCooldown is how long the invite number of a removed round rests before it is handed out again,
+	// so stale invitations do not lead into a stranger's round.
+	inviteRecycleCooldown = time.Hour
+	// inviteDrawAttempts is how many random invite numbers Allocate draws before giving up.
+	inviteDrawAttempts = 32
+)
+
+// InviteCodeAllocator hands out invite numbers no round uses yet.
+// Each allocated number stays reserved until a round uses it or the reservation lapses,
+// so concurrent creations never receive the same number. Numbers of removed rounds are
+// recycled once they have rested for a cool-down.
+// It is safe for concurrent use.
+type InviteCodeAllocator struct {
+	mu       sync.Mutex
+	format   InviteFormat
+	now      func() time.Time
+	inUse    map[string]struct{}  // Numbers used by a round.
+	reserved map[string]time.Time // {key: inviteNo, value: end of the reservation}
+	resting  map[string]time.Time // {key: inviteNo, value: end of the cool-down}
+	freed    []string             // Numbers of removed rounds, oldest first.
+}
+
+// NewInviteCodeAllocator creates an InviteCodeAllocator drawing numbers in the given format.
+func NewInviteCodeAllocator(format InviteFormat) *InviteCodeAllocator {
+	return &InviteCodeAllocator{
+		format:   format,
+		now:      time.Now,
+		inUse:    make(map[string]struct{}),
+		reserved: make(map[string]time.Time),
+		resting:  make(map[string]time.Time),
+	}
+}
+
+// SetFormat sets the format of the numbers drawn from now on.
+func (a *InviteCodeAllocator) SetFormat(format InviteFormat) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.format = format
+}
+
+// Format returns the format of the numbers drawn from now on.
+func (a *InviteCodeAllocator) Format() InviteFormat {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.format
+}
+
+// Allocate reserves an invite number that is neither used, reserved nor resting.
+// Recycled numbers whose cool-down is over are handed out before new ones are drawn.
+// It returns ErrInviteNoExhausted if no free number turns up.
+func (a *InviteCodeAllocator) Allocate() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if inviteNo, ok := a.recycleLocked(now); ok {
+		a.reserved[inviteNo] = now.Add(inviteReservationTTL)
+		return inviteNo, nil
+	}
+	for range inviteDrawAttempts {
+		inviteNo, err := a.format.Draw()
+		if err != nil {
+			return "", err
+		}
+		if a.availableLocked(inviteNo, now) {
+			a.reserved[inviteNo] = now.Add(inviteReservationTTL)
+			return inviteNo, nil
+		}
+	}
+	return "", ErrInviteNoExhausted
+}
+
+// Use marks the invite number as used by a round, consuming its reservation.
+func (a *InviteCodeAllocator) Use(inviteNo string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.reserved, inviteNo)
+	delete(a.resting, inviteNo)
+	a.inUse[inviteNo] = struct{}{}
+}
+
+// Release gives the invite number back. A number used by a round rests for a cool-down
+// before it is recycled; a number that was only reserved is free at once.
+func (a *InviteCodeAllocator) Release(inviteNo string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.reserved, inviteNo)
+	if _, ok := a.inUse[inviteNo]; !ok {
+		return
+	}
+	delete(a.inUse, inviteNo)
+	a.resting[inviteNo] = a.now().Add(inviteRecycleCooldown)
+	a.freed = append(a.freed, inviteNo)
+}
+
+// recycleLocked pops the oldest freed number that rested long enough and fits the current format.
+// Numbers taken again meanwhile, or of another format, are dropped. The caller must hold a.mu.
+func (a *InviteCodeAllocator) recycleLocked(now time.Time) (string, bool) {
+	for len(a.freed) > 0 {
+		inviteNo := a.freed[0]
+		if until, ok := a.resting[inviteNo]; ok && now.Before(until) {
+			// Numbers rest equally long, so the ones behind are not ready either.
+			return "", false
+		}
+		a.freed = a.freed[1:]
+		if normalized, ok := a.format.Normalize(inviteNo); !ok || normalized != inviteNo {
+			continue
+		}
+		if a.availableLocked(inviteNo, now) {
+			return inviteNo, true
+		}
+	}
+	return "", false
+}
+
+// availableLocked reports whether the number is free to reserve, forgetting lapsed reservations
+// and finished cool-downs on the way. The caller must hold a.mu.
+func (a *InviteCodeAllocator) availableLocked(inviteNo string, now time.Time) bool {
+	if _, ok := a.inUse[inviteNo]; ok {
+		return false
+	}
+	if until, ok := a.reserved[inviteNo]; ok {
+		if now.Before(until) {
+			return false
+		}
+		delete(a.reserved, inviteNo)
+	}
+	if until, ok := a.resting[inviteNo]; ok {
+		if now.Before(until) {
+			return false
+		}
+		delete(a.resting, inviteNo)
+	}
+	return true
+}
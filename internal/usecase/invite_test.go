@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+	"werewolve-helper/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxShuffler always draws the largest number.
+type maxShuffler struct{}
+
+func (maxShuffler) Shuffle(int, func(i, j int)) error { return nil }
+func (maxShuffler) IntN(n int) (int, error)           { return n - 1, nil }
+
+// fixedFormat draws its numbers in turn, so tests control every collision.
+type fixedFormat struct {
+	codes []string
+	next  int
+}
+
+func (f *fixedFormat) Name() string { return "fixed" }
+
+func (f *fixedFormat) Draw() (string, error) {
+	code := f.codes[f.next%len(f.codes)]
+	f.next++
+	return code, nil
+}
+
+func (f *fixedFormat) Normalize(text string) (string, bool) { return text, true }
+
+func TestInviteFormats_Draw(t *testing.T) {
+	orig := domain.Rng
+	t.Cleanup(func() { domain.Rng = orig })
+	domain.Rng = maxShuffler{}
+
+	tests := []struct {
+		format InviteFormat
+		want   string
+	}{
+		{DigitsFormat{}, "999999"},
+		{WordsFormat{}, "bell-bell-99"},
+		{Base32Format{Length: 8}, "99999999"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.format.Draw()
+		require.NoError(t, err, tt.format.Name())
+		assert.Equal(t, tt.want, got, tt.format.Name())
+		normalized, ok := NormalizeInviteNo(got)
+		assert.True(t, ok, got)
+		assert.Equal(t, got, normalized)
+	}
+}
+
+func TestLookupInviteFormat(t *testing.T) {
+	f, err := LookupInviteFormat("", 0)
+	require.NoError(t, err)
+	assert.Equal(t, DigitsInviteFormat, f.Name())
+
+	f, err = LookupInviteFormat(WordsInviteFormat, 0)
+	require.NoError(t, err)
+	assert.Equal(t, WordsInviteFormat, f.Name())
+
+	f, err = LookupInviteFormat(Base32InviteFormat, 0)
+	require.NoError(t, err)
+	assert.Equal(t, Base32Format{Length: DefaultBase32InviteNoLength}, f)
+
+	_, err = LookupInviteFormat(Base32InviteFormat, DefaultInviteNoLength)
+	require.ErrorIs(t, err, ErrInviteNoLength)
+	_, err = LookupInviteFormat(Base32InviteFormat, MaxInviteNoLength+1)
+	require.ErrorIs(t, err, ErrInviteNoLength)
+	_, err = LookupInviteFormat("emoji", 0)
+	require.ErrorIs(t, err, ErrInviteFormat)
+}
+
+func TestNormalizeInviteNo(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{"123456", "123456", true},
+		{"12345", "", false},
+		{"12a456", "", false},
+		{"abcd2345", "ABCD2345", true},
+		{"ABCD0123", "", false},
+		{"ABCDEFGHJKLMNPQRS", "", false},
+		{"wolf-moon-42", "wolf-moon-42", true},
+		{"Wolf-Moon-07", "wolf-moon-07", true},
+		{"wolf-cat-42", "", false},
+		{"wolf-moon-4", "", false},
+		{"wolf-moon-4x", "", false},
+		{"wolf-moon", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := NormalizeInviteNo(tt.text)
+		assert.Equal(t, tt.ok, ok, tt.text)
+		assert.Equal(t, tt.want, got, tt.text)
+	}
+}
+
+func TestRoundManager_ParseInviteNo(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "ABCD2345", 1)))
+	assert := assert.New(t)
+
+	got, ok := m.ParseInviteNo("123456")
+	assert.True(ok)
+	assert.Equal("123456", got)
+	_, ok = m.ParseInviteNo("hahahaha")
+	assert.False(ok, "Words are not invite numbers under the digits format")
+	got, ok = m.ParseInviteNo("abcd2345")
+	assert.True(ok, "Rounds of an earlier format stay reachable")
+	assert.Equal("ABCD2345", got)
+
+	m.SetInviteFormat(Base32Format{Length: 8})
+	got, ok = m.ParseInviteNo("hahahaha")
+	assert.True(ok)
+	assert.Equal("HAHAHAHA", got)
+	_, ok = m.ParseInviteNo("654321")
+	assert.False(ok)
+}
+
+func TestInviteCodeAllocator_Reserves(t *testing.T) {
+	format := &fixedFormat{codes: []string{"A", "A", "B"}}
+	a := NewInviteCodeAllocator(format)
+
+	first, err := a.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, "A", first)
+
+	// "A" is reserved, so the next draw is skipped.
+	second, err := a.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, "B", second)
+
+	format.codes = []string{"A", "B"}
+	_, err = a.Allocate()
+	require.ErrorIs(t, err, ErrInviteNoExhausted)
+
+	// A reservation no round used is free again at once.
+	a.Release("B")
+	got, err := a.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, "B", got)
+}
+
+func TestInviteCodeAllocator_ReservationLapses(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewInviteCodeAllocator(&fixedFormat{codes: []string{"A"}})
+	a.now = func() time.Time { return now }
+
+	_, err := a.Allocate()
+	require.NoError(t, err)
+	_, err = a.Allocate()
+	require.ErrorIs(t, err, ErrInviteNoExhausted)
+
+	now = now.Add(inviteReservationTTL)
+	got, err := a.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, "A", got)
+}
+
+func TestInviteCodeAllocator_Recycles(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewInviteCodeAllocator(&fixedFormat{codes: []string{"C"}})
+	a.now = func() time.Time { return now }
+
+	a.Use("A")
+	a.Use("B")
+	a.Release("A")
+	a.Release("B")
+
+	// Freed numbers rest before they are handed out again.
+	got, err := a.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, "C", got)
+	_, err = a.Allocate()
+	require.ErrorIs(t, err, ErrInviteNoExhausted)
+
+	now = now.Add(inviteRecycleCooldown)
+	got, err = a.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, "A", got)
+	got, err = a.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, "B", got)
+}
+
+func TestInviteCodeAllocator_SkipsOtherFormats(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewInviteCodeAllocator(DigitsFormat{})
+	a.now = func() time.Time { return now }
+
+	a.Use("wolf-moon-42")
+	a.Release("wolf-moon-42")
+	now = now.Add(inviteRecycleCooldown)
+
+	got, err := a.Allocate()
+	require.NoError(t, err)
+	assert.Len(t, got, DefaultInviteNoLength)
+}
+
+func TestInviteCodeAllocator_Concurrent(t *testing.T) {
+	orig := domain.Rng
+	t.Cleanup(func() { domain.Rng = orig })
+	// A tiny space forces collisions between the goroutines.
+	domain.Rng = domain.NewSeededShuffler(3)
+	a := NewInviteCodeAllocator(Base32Format{Length: 2})
+
+	const n = 200
+	codes := make([]string, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, err := a.Allocate()
+			assert.NoError(t, err)
+			codes[i] = code
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		assert.False(t, seen[code], "allocated twice: %s", code)
+		seen[code] = true
+	}
+}
+
+func TestRoundManager_NewInviteNo(t *testing.T) {
+	m := newTestManager(t)
+	assert := assert.New(t)
+
+	inviteNo, err := m.NewInviteNo()
+	require.NoError(t, err)
+	assert.Len(inviteNo, DefaultInviteNoLength)
+	_, ok := NormalizeInviteNo(inviteNo)
+	assert.True(ok)
+
+	m.SetInviteFormat(WordsFormat{})
+	inviteNo, err = m.NewInviteNo()
+	require.NoError(t, err)
+	normalized, ok := NormalizeInviteNo(inviteNo)
+	assert.True(ok)
+	assert.Equal(inviteNo, normalized)
+}
+
+func TestRoundManager_NewInviteNo_SkipsUsed(t *testing.T) {
+	orig := domain.Rng
+	t.Cleanup(func() { domain.Rng = orig })
+	m := newTestManager(t)
+
+	// The same seed draws the same number twice: the second draw must not reuse it.
+	domain.Rng = domain.NewSeededShuffler(7)
+	used, err := m.NewInviteNo()
+	require.NoError(t, err)
+	require.NoError(t, m.Create(newTestRound("owner1", used, 1)))
+
+	domain.Rng = domain.NewSeededShuffler(7)
+	inviteNo, err := m.NewInviteNo()
+	require.NoError(t, err)
+	assert.NotEqual(t, used, inviteNo)
+}
+
+func TestRoundManager_ConcurrentCreate(t *testing.T) {
+	m := newTestManager(t)
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			inviteNo, err := m.NewInviteNo()
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, m.Create(newTestRound(fmt.Sprintf("owner%d", i), inviteNo, 1)))
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, m.rounds, n)
+	assert.Len(t, m.invites, n)
+}
+
+func TestRoundManager_RecyclesRemovedInviteNo(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := newTestManager(t)
+	m.codes = NewInviteCodeAllocator(&fixedFormat{codes: []string{"000001"}})
+	m.codes.now = func() time.Time { return now }
+
+	inviteNo, err := m.NewInviteNo()
+	require.NoError(t, err)
+	require.NoError(t, m.Create(newTestRound("owner1", inviteNo, 1)))
+	m.Expire("owner1")
+
+	_, err = m.NewInviteNo()
+	require.ErrorIs(t, err, ErrInviteNoExhausted)
+
+	now = now.Add(inviteRecycleCooldown)
+	got, err := m.NewInviteNo()
+	require.NoError(t, err)
+	assert.Equal(t, inviteNo, got)
+}
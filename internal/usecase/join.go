@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"werewolve-helper/internal/domain"
+)
+
+// JoinStatus is the outcome of a user joining a round by invite number.
+type JoinStatus int
+
+// Constants for the join outcomes.
+const (
+	JoinJoined        JoinStatus = iota + 1 // The user joined and was dealt an identity.
+	JoinAlreadyJoined                       // The user had already joined; their identity is unchanged.
+	JoinFull                                // Every identity is taken.
+	JoinExpired                             // The round expired and was removed.
+	JoinNotFound                            // No round has the invite number.
+)
+
+// String returns the string representation of a JoinStatus.
+func (s JoinStatus) String() string {
+	switch s {
+	case JoinJoined:
+		return "joined"
+	case JoinAlreadyJoined:
+		return "already_joined"
+	case JoinFull:
+		return "full"
+	case JoinExpired:
+		return "expired"
+	case JoinNotFound:
+		return "not_found"
+	default:
+		return "unknown"
+	}
+}
+
+// JoinResult is what happened when a user tried to join a round.
+type JoinResult struct {
+	Status      JoinStatus         // Outcome of the join.
+	Participant domain.Participant // The user in the round, for JoinJoined and JoinAlreadyJoined.
+	Round       RoundSummary       // The round after the join; empty for JoinNotFound and JoinExpired.
+}
+
+// Join registers a user into the round with the given invite number.
+// The round is looked up by invite number only, so anyone holding the number can join, not just the owner.
+// Expired rounds are removed.
+func (m *RoundManager) Join(inviteNo, userID, name, pictureURL string) JoinResult {
+	m.mu.Lock()
+	defer m.publishPending()
+	defer m.mu.Unlock()
+
+	r, ok := m.findByInviteNoLocked(inviteNo)
+	if !ok {
+		return JoinResult{Status: JoinNotFound}
+	}
+	if r.IsExpired() {
+		m.removeLocked(r.OwnerID)
+		return JoinResult{Status: JoinExpired}
+	}
+	if ok, p := r.IsRegistrationDuplicate(userID); ok {
+		return JoinResult{Status: JoinAlreadyJoined, Participant: *p, Round: roundSummary(r)}
+	}
+	if r.Register(userID, name, pictureURL) == "" {
+		return JoinResult{Status: JoinFull, Round: roundSummary(r)}
+	}
+	m.saveLocked(r)
+	if r.IsRegistrationClose() {
+		m.emitLocked(RoundFilled, r)
+	}
+	_, p := r.IsRegistrationDuplicate(userID)
+	return JoinResult{Status: JoinJoined, Participant: *p, Round: roundSummary(r)}
+}
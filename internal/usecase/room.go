@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"werewolve-helper/internal/domain"
+)
+
+// RoomView is a host's view of a round, shown on the room dashboard.
+type RoomView struct {
+	OwnerID      string               // Owner of the round.
+	InviteNo     string               // Invitation number of the round.
+	ExpiredAt    time.Time            // Time when the round expires.
+	Rules        domain.Rules         // House rules of the round.
+	Identities   []domain.Identity    // Identities of the round, dealt or not.
+	Participants []domain.Participant // Players who joined, in registration order; identities cleared unless RolesVisible.
+	GameStarted  bool                 // Whether the game engine is running or has run.
+	Replacing    string               // Participant the next user to join replaces, if any.
+	CoHosts      []domain.CoHost      // Users the owner lets help moderate.
+	OwnerPlays   bool                 // Whether the owner plays instead of moderating.
+	RolesVisible bool                 // Whether the viewer may see the identities of the participants.
+	CanModerate  bool                 // Whether the viewer may start the game engine.
+	CanManage    bool                 // Whether the viewer may kick, seat and close.
+	CanGrant     bool                 // Whether the viewer may revoke co-hosts.
+}
+
+// Room returns the dashboard view of the round with the given invite number. Only the hosts may see it,
+// and only those who may see the roles get the identities of the participants.
+func (m *RoundManager) Room(inviteNo, userID string) (RoomView, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, userID, domain.PermOpenRoom)
+	if err != nil {
+		return RoomView{}, err
+	}
+	view := RoomView{
+		OwnerID:      r.OwnerID,
+		InviteNo:     r.InviteNo,
+		ExpiredAt:    r.ExpiredAt,
+		Rules:        r.Rules,
+		Identities:   append([]domain.Identity(nil), r.Identities...),
+		Participants: append([]domain.Participant(nil), r.Participants...),
+		GameStarted:  r.Game != nil,
+		Replacing:    r.Replacing,
+		CoHosts:      append([]domain.CoHost(nil), r.CoHosts...),
+		OwnerPlays:   r.OwnerPlays,
+		RolesVisible: r.Can(userID, domain.PermViewRoles),
+		CanModerate:  r.Can(userID, domain.PermModerate),
+		CanManage:    r.Can(userID, domain.PermManage),
+		CanGrant:     r.Can(userID, domain.PermGrant),
+	}
+	if !view.RolesVisible {
+		for i := range view.Participants {
+			view.Participants[i].Identity = 0
+		}
+	}
+	return view, nil
+}
+
+// Kick removes a participant from the round with the given invite number on behalf of the owner.
+func (m *RoundManager) Kick(inviteNo, ownerID, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, ownerID, domain.PermManage)
+	if err != nil {
+		return err
+	}
+	if err := r.Kick(ownerID, userID); err != nil {
+		return err
+	}
+	m.saveLocked(r)
+	return nil
+}
+
+// Leave removes the user from the round with the given invite number before the game starts.
+func (m *RoundManager) Leave(inviteNo, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.memberRoundLocked(inviteNo, userID)
+	if !ok {
+		return ErrRoundNotFound
+	}
+	if err := r.Leave(userID); err != nil {
+		return err
+	}
+	m.saveLocked(r)
+	return nil
+}
+
+// Replace marks a participant of the round with the given invite number, on behalf of the owner,
+// to be replaced by the next user who joins. The newcomer takes over their identity and seat.
+func (m *RoundManager) Replace(inviteNo, ownerID, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, ownerID, domain.PermManage)
+	if err != nil {
+		return err
+	}
+	if err := r.StartReplace(ownerID, userID); err != nil {
+		return err
+	}
+	m.saveLocked(r)
+	return nil
+}
+
+// Close removes the round with the given invite number on behalf of the user.
+func (m *RoundManager) Close(inviteNo, userID string) error {
+	m.mu.Lock()
+	defer m.publishPending()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, userID, domain.PermManage)
+	if err != nil {
+		return err
+	}
+	m.removeLocked(r.OwnerID)
+	return nil
+}
+
+// authorizeLocked finds the round with the invite number and checks that the user holds the permission on it.
+// Every privileged operation goes through it. The caller must hold m.mu.
+func (m *RoundManager) authorizeLocked(inviteNo, userID string, perm domain.Permission) (*domain.Round, error) {
+	r, ok := m.memberRoundLocked(inviteNo, userID)
+	if !ok {
+		return nil, ErrRoundNotFound
+	}
+	if !r.Can(userID, perm) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotPermitted, perm)
+	}
+	return r, nil
+}
+
+// IsMember reports whether the user owns, co-hosts or plays in the round with the given invite number.
+func (m *RoundManager) IsMember(inviteNo, userID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.memberRoundLocked(inviteNo, userID)
+	return ok
+}
+
+// memberRoundLocked finds the round with the invite number if the user is a member of it.
+// Users with no part in a round are told it does not exist, the same as for a wrong number,
+// so that guessing invite numbers learns nothing. The caller must hold m.mu.
+func (m *RoundManager) memberRoundLocked(inviteNo, userID string) (*domain.Round, bool) {
+	r, ok := m.findByInviteNoLocked(inviteNo)
+	if !ok || !r.IsMember(userID) {
+		return nil, false
+	}
+	return r, true
+}
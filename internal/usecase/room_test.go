@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"testing"
+	"werewolve-helper/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundManager_Room(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+	assert := assert.New(t)
+
+	room, err := m.Room("000001", "owner1")
+	require.NoError(t, err)
+	assert.Equal("000001", room.InviteNo)
+	assert.Len(room.Identities, 2)
+	require.Len(t, room.Participants, 1)
+	assert.Equal("url1", room.Participants[0].PictureURL)
+	assert.False(room.GameStarted)
+
+	_, err = m.Room("000001", "user1")
+	assert.ErrorIs(err, domain.ErrNotPermitted, "Only the owner may see the dashboard")
+	_, err = m.Room("999999", "owner1")
+	assert.ErrorIs(err, ErrRoundNotFound)
+}
+
+func TestRoundManager_Kick(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+	assert := assert.New(t)
+
+	assert.ErrorIs(m.Kick("000001", "user1", "user1"), domain.ErrNotPermitted)
+	assert.ErrorIs(m.Kick("000001", "owner1", "user2"), domain.ErrNotParticipant)
+	assert.NoError(m.Kick("000001", "owner1", "user1"))
+
+	room, err := m.Room("000001", "owner1")
+	require.NoError(t, err)
+	assert.Empty(room.Participants)
+
+	// The kicked player can join again.
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+}
+
+func TestRoundManager_Leave(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+	assert := assert.New(t)
+
+	assert.ErrorIs(m.Leave("000001", "user2"), ErrRoundNotFound, "Strangers cannot tell the round exists")
+	assert.ErrorIs(m.Leave("000001", "owner1"), domain.ErrNotParticipant)
+	assert.ErrorIs(m.Leave("999999", "user1"), ErrRoundNotFound)
+	assert.NoError(m.Leave("000001", "user1"))
+
+	room, err := m.Room("000001", "owner1")
+	require.NoError(t, err)
+	assert.Empty(room.Participants)
+}
+
+func TestRoundManager_Replace(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 1)))
+	first := mustJoin(t, m, "000001", "user1", "User One", "url1")
+	assert := assert.New(t)
+
+	assert.ErrorIs(m.Replace("000001", "user1", "user1"), domain.ErrNotPermitted)
+	assert.ErrorIs(m.Replace("000001", "owner1", "user2"), domain.ErrNotParticipant)
+	require.Equal(t, JoinFull, m.Join("000001", "user2", "User Two", "url2").Status)
+
+	require.NoError(t, m.Replace("000001", "owner1", "user1"))
+	room, err := m.Room("000001", "owner1")
+	require.NoError(t, err)
+	assert.Equal("user1", room.Replacing)
+
+	p := mustJoin(t, m, "000001", "user2", "User Two", "url2")
+	assert.Equal("user2", p.UserID)
+	assert.Equal(first.Identity, p.Identity)
+	assert.Equal(first.Seat, p.Seat)
+}
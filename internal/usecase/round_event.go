@@ -0,0 +1,42 @@
+package usecase
+
+import "time"
+
+// RoundEventType represents a lifecycle change of a round.
+type RoundEventType int
+
+// Constants for the round lifecycle events.
+const (
+	RoundCreated    RoundEventType = iota + 1 // The owner created the round.
+	RoundFilled                               // The last identity was taken.
+	RoundReshuffled                           // The owner started another game with the same identities.
+	RoundExpired                              // The round was removed or expired.
+)
+
+// String returns the string representation of a RoundEventType.
+func (t RoundEventType) String() string {
+	switch t {
+	case RoundCreated:
+		return "created"
+	case RoundFilled:
+		return "filled"
+	case RoundReshuffled:
+		return "reshuffled"
+	case RoundExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// RoundEvent describes a lifecycle change of a round.
+type RoundEvent struct {
+	Type     RoundEventType // Kind of change.
+	OwnerID  string         // Owner of the round.
+	InviteNo string         // Invitation number of the round.
+	At       time.Time      // Time when the change happened.
+}
+
+// RoundEventHandler receives round lifecycle events.
+// Handlers run synchronously after the change is committed, so slow work should be handed off to a goroutine.
+type RoundEventHandler func(RoundEvent)
@@ -1 +1,315 @@
 package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+	"werewolve-helper/internal/domain"
+)
+
+// Errors returned by RoundManager operations.
+var (
+	ErrRoundNotFound     = errors.New("round not found")
+	ErrInviteNoDuplicate = errors.New("invite number already in use")
+	ErrGroupNotBound     = errors.New("no round bound to the group")
+)
+
+// RoundSummary is what a chat needs to know about a round it can join.
+type RoundSummary struct {
+	OwnerID      string            // Owner of the round.
+	InviteNo     string            // Invitation number of the round.
+	Identities   []domain.Identity // Identities dealt in the round.
+	Participants int               // Number of players who joined so far.
+}
+
+// RoundManager owns every open round and serializes access to them.
+// Rounds are keyed by owner ID, with secondary indexes on invite number and bound group.
+// Every change is written through to the repository; the in-memory state stays authoritative.
+type RoundManager struct {
+	mu      sync.Mutex
+	repo    RoundRepository
+	rounds  map[string]*domain.Round // {key: ownerID, value: Round}
+	invites map[string]string        // {key: inviteNo, value: ownerID}
+	groups  map[string]string        // {key: groupID, value: ownerID}
+	cohosts map[string][]string      // {key: co-host userID, value: ownerIDs in the order of their grants}
+
+	codes *InviteCodeAllocator // Hands out the invite numbers of new rounds.
+
+	subscribers []RoundEventHandler
+	pending     []RoundEvent // Events raised under m.mu, published once it is released.
+}
+
+// NewRoundManager creates a RoundManager and loads the rounds stored in repo.
+func NewRoundManager(repo RoundRepository) (*RoundManager, error) {
+	m := &RoundManager{
+		repo:    repo,
+		rounds:  make(map[string]*domain.Round),
+		invites: make(map[string]string),
+		groups:  make(map[string]string),
+		cohosts: make(map[string][]string),
+
+		codes: NewInviteCodeAllocator(DigitsFormat{}),
+	}
+
+	stored, err := repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range stored {
+		m.rounds[r.OwnerID] = r
+		m.invites[r.InviteNo] = r.OwnerID
+		m.codes.Use(r.InviteNo)
+		if r.GroupID != "" {
+			m.groups[r.GroupID] = r.OwnerID
+		}
+		for _, c := range r.CoHosts {
+			m.cohosts[c.UserID] = append(m.cohosts[c.UserID], r.OwnerID)
+		}
+	}
+	// Restore the order of the grants, so the latest one still wins after a restart.
+	for userID, owners := range m.cohosts {
+		slices.SortFunc(owners, func(a, b string) int {
+			return cmp.Or(coHostGrantedAt(m.rounds[a], userID).Compare(coHostGrantedAt(m.rounds[b], userID)), cmp.Compare(a, b))
+		})
+	}
+	return m, nil
+}
+
+// Create stores a new round for its owner, replacing any round the owner already had.
+// The manager takes ownership of round; callers must not modify it afterwards.
+// It returns ErrInviteNoDuplicate if another owner's round uses the same invite number.
+func (m *RoundManager) Create(round *domain.Round) error {
+	m.mu.Lock()
+	defer m.publishPending()
+	defer m.mu.Unlock()
+
+	if ownerID, ok := m.invites[round.InviteNo]; ok && ownerID != round.OwnerID {
+		return ErrInviteNoDuplicate
+	}
+	m.removeLocked(round.OwnerID)
+	m.rounds[round.OwnerID] = round
+	m.invites[round.InviteNo] = round.OwnerID
+	m.codes.Use(round.InviteNo)
+	m.saveLocked(round)
+	m.emitLocked(RoundCreated, round)
+	return nil
+}
+
+// Look returns the invite number and the participants info of the round the user hosts.
+// The info is empty if the user may not see the roles.
+func (m *RoundManager) Look(userID string) (inviteNo, info string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.hostedLocked(userID)
+	if !ok {
+		return "", "", ErrRoundNotFound
+	}
+	return r.InviteNo, r.GetParticipantsInfoReplyMessage(userID), nil
+}
+
+// FindByInviteNo returns the round with the given invite number.
+func (m *RoundManager) FindByInviteNo(inviteNo string) (RoundSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.findByInviteNoLocked(inviteNo)
+	if !ok {
+		return RoundSummary{}, ErrRoundNotFound
+	}
+	return roundSummary(r), nil
+}
+
+// Teammates returns the participants the user knows as teammates in the round with the given invite number.
+func (m *RoundManager) Teammates(inviteNo, userID string) ([]domain.Participant, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.findByInviteNoLocked(inviteNo)
+	if !ok {
+		return nil, ErrRoundNotFound
+	}
+	return r.Teammates(userID), nil
+}
+
+// TeamReveals returns what every wolf of the round with the given invite number may learn about the pack.
+func (m *RoundManager) TeamReveals(inviteNo string) ([]domain.TeamReveal, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.findByInviteNoLocked(inviteNo)
+	if !ok {
+		return nil, ErrRoundNotFound
+	}
+	return r.TeamReveals(), nil
+}
+
+// Composition returns the identities and rules of the owner's round.
+func (m *RoundManager) Composition(ownerID string) ([]domain.Identity, domain.Rules, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.rounds[ownerID]
+	if !ok {
+		return nil, domain.Rules{}, ErrRoundNotFound
+	}
+	return append([]domain.Identity(nil), r.Identities...), r.Rules, nil
+}
+
+// Again reshuffles the round with the given invite number for a new game on behalf of the user.
+func (m *RoundManager) Again(inviteNo, userID string) error {
+	m.mu.Lock()
+	defer m.publishPending()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, userID, domain.PermReshuffle)
+	if err != nil {
+		return err
+	}
+	r.Again()
+	m.saveLocked(r)
+	m.emitLocked(RoundReshuffled, r)
+	// A playing owner is dealt again at once, which may fill a single-seat round.
+	if r.IsRegistrationClose() {
+		m.emitLocked(RoundFilled, r)
+	}
+	return nil
+}
+
+// Expire removes the owner's round, if any.
+func (m *RoundManager) Expire(ownerID string) {
+	m.mu.Lock()
+	defer m.publishPending()
+	defer m.mu.Unlock()
+
+	m.removeLocked(ownerID)
+}
+
+// RemoveExpired evicts every expired round and returns how many were removed.
+func (m *RoundManager) RemoveExpired() int {
+	m.mu.Lock()
+	defer m.publishPending()
+	defer m.mu.Unlock()
+
+	n := 0
+	for ownerID, r := range m.rounds {
+		if r.IsExpired() {
+			m.removeLocked(ownerID)
+			n++
+		}
+	}
+	return n
+}
+
+// RunJanitor evicts expired rounds every interval until ctx is done.
+func (m *RoundManager) RunJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := m.RemoveExpired(); n > 0 {
+				log.Printf("janitor removed %d expired rounds", n)
+			}
+		}
+	}
+}
+
+// Subscribe registers a handler for round lifecycle events.
+func (m *RoundManager) Subscribe(handler RoundEventHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subscribers = append(m.subscribers, handler)
+}
+
+// HasInviteNo reports whether an open round uses the given invite number.
+func (m *RoundManager) HasInviteNo(inviteNo string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.invites[inviteNo]
+	return ok
+}
+
+// findByInviteNoLocked looks up a round by invite number. The caller must hold m.mu.
+func (m *RoundManager) findByInviteNoLocked(inviteNo string) (*domain.Round, bool) {
+	ownerID, ok := m.invites[inviteNo]
+	if !ok {
+		return nil, false
+	}
+	r, ok := m.rounds[ownerID]
+	return r, ok
+}
+
+// removeLocked deletes the owner's round and its indexes. The caller must hold m.mu.
+func (m *RoundManager) removeLocked(ownerID string) {
+	r, ok := m.rounds[ownerID]
+	if !ok {
+		return
+	}
+	delete(m.invites, r.InviteNo)
+	m.codes.Release(r.InviteNo)
+	if r.GroupID != "" {
+		delete(m.groups, r.GroupID)
+	}
+	for _, c := range r.CoHosts {
+		m.unindexCoHostLocked(c.UserID, ownerID)
+	}
+	delete(m.rounds, ownerID)
+	m.emitLocked(RoundExpired, r)
+	if err := m.repo.Delete(ownerID); err != nil {
+		log.Printf("delete round %s error: %v", ownerID, err)
+	}
+}
+
+// saveLocked writes the round through to the repository. The caller must hold m.mu.
+// A failed write is logged but does not fail the operation, since memory stays authoritative.
+func (m *RoundManager) saveLocked(r *domain.Round) {
+	if err := m.repo.Save(r); err != nil {
+		log.Printf("save round %s error: %v", r.OwnerID, err)
+	}
+}
+
+// emitLocked queues a lifecycle event of the round. The caller must hold m.mu.
+func (m *RoundManager) emitLocked(t RoundEventType, r *domain.Round) {
+	m.pending = append(m.pending, RoundEvent{
+		Type:     t,
+		OwnerID:  r.OwnerID,
+		InviteNo: r.InviteNo,
+		At:       time.Now(),
+	})
+}
+
+// publishPending delivers queued events to the subscribers. It must be called without holding m.mu,
+// so that handlers are free to call back into the manager.
+func (m *RoundManager) publishPending() {
+	m.mu.Lock()
+	events := m.pending
+	m.pending = nil
+	subscribers := m.subscribers
+	m.mu.Unlock()
+
+	for _, e := range events {
+		for _, handler := range subscribers {
+			handler(e)
+		}
+	}
+}
+
+// roundSummary snapshots the round for a chat.
+func roundSummary(r *domain.Round) RoundSummary {
+	return RoundSummary{
+		OwnerID:      r.OwnerID,
+		InviteNo:     r.InviteNo,
+		Identities:   append([]domain.Identity(nil), r.Identities...),
+		Participants: len(r.Participants),
+	}
+}
@@ -1 +1,333 @@
 package usecase
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T) *RoundManager {
+	t.Helper()
+	m, err := NewRoundManager(storage.NewMemoryRoundRepository())
+	require.NoError(t, err)
+	return m
+}
+
+func newTestRound(ownerID, inviteNo string, villagers int) *domain.Round {
+	r := domain.NewRound(ownerID, inviteNo)
+	r.SetIdentity(ownerID, domain.Villager, villagers)
+	return r
+}
+
+// mustJoin joins the round and fails the test unless the user was dealt an identity.
+func mustJoin(t *testing.T, m *RoundManager, inviteNo, userID, name, pictureURL string) domain.Participant {
+	t.Helper()
+	res := m.Join(inviteNo, userID, name, pictureURL)
+	require.Equal(t, JoinJoined, res.Status)
+	return res.Participant
+}
+
+func TestNewRoundManager_LoadsRepository(t *testing.T) {
+	repo := storage.NewMemoryRoundRepository()
+	require.NoError(t, repo.Save(newTestRound("owner1", "000001", 1)))
+
+	m, err := NewRoundManager(repo)
+	require.NoError(t, err)
+	assert.True(t, m.HasInviteNo("000001"), "Stored round should be indexed on start")
+
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+	rounds, err := repo.FindAll()
+	require.NoError(t, err)
+	require.Len(t, rounds, 1)
+	assert.Len(t, rounds[0].Participants, 1, "Join should be written through to the repository")
+
+	m.Expire("owner1")
+	rounds, err = repo.FindAll()
+	require.NoError(t, err)
+	assert.Empty(t, rounds, "Expire should delete from the repository")
+}
+
+func TestRoundManager_Create(t *testing.T) {
+	m := newTestManager(t)
+	assert := assert.New(t)
+
+	assert.NoError(m.Create(newTestRound("owner1", "000001", 1)))
+	assert.True(m.HasInviteNo("000001"), "Invite number should be indexed")
+
+	// Another owner cannot reuse the invite number.
+	assert.ErrorIs(m.Create(newTestRound("owner2", "000001", 1)), ErrInviteNoDuplicate)
+
+	// The same owner replaces the previous round and its index.
+	assert.NoError(m.Create(newTestRound("owner1", "000002", 1)))
+	assert.False(m.HasInviteNo("000001"), "Old invite number should be released")
+	assert.True(m.HasInviteNo("000002"), "New invite number should be indexed")
+}
+
+func TestRoundManager_Join(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 1)))
+	assert := assert.New(t)
+
+	assert.Equal(JoinNotFound, m.Join("999999", "user1", "User One", "url1").Status)
+
+	// A user other than the owner can join by invite number.
+	res := m.Join("000001", "user1", "User One", "url1")
+	assert.Equal(JoinJoined, res.Status)
+	assert.Equal("user1", res.Participant.UserID)
+	assert.Equal(domain.Villager, res.Participant.Identity)
+	assert.Equal("owner1", res.Round.OwnerID)
+	assert.Equal(1, res.Round.Participants)
+
+	res = m.Join("000001", "user1", "User One", "url1")
+	assert.Equal(JoinAlreadyJoined, res.Status)
+	assert.Equal(domain.Villager, res.Participant.Identity, "Duplicate join should return the existing identity")
+
+	res = m.Join("000001", "user2", "User Two", "url2")
+	assert.Equal(JoinFull, res.Status)
+	assert.Empty(res.Participant.UserID)
+}
+
+func TestRoundManager_Join_Expired(t *testing.T) {
+	m := newTestManager(t)
+	r := newTestRound("owner1", "000001", 1)
+	r.ExpiredAt = time.Now().Add(-time.Minute)
+	require.NoError(t, m.Create(r))
+
+	assert.Equal(t, JoinExpired, m.Join("000001", "user1", "User One", "url1").Status)
+	assert.False(t, m.HasInviteNo("000001"), "Expired round should be removed")
+	assert.Equal(t, JoinNotFound, m.Join("000001", "user1", "User One", "url1").Status)
+}
+
+func TestJoinStatus_String(t *testing.T) {
+	assert.Equal(t, "already_joined", JoinAlreadyJoined.String())
+	assert.Equal(t, "unknown", JoinStatus(0).String())
+}
+
+func TestRoundManager_LookAndAgain(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+	assert := assert.New(t)
+
+	inviteNo, info, err := m.Look("owner1")
+	assert.NoError(err)
+	assert.Equal("000001", inviteNo)
+	assert.Contains(info, "目前參與人數: 1/2")
+
+	_, _, err = m.Look("user1")
+	assert.ErrorIs(err, ErrRoundNotFound)
+
+	assert.NoError(m.Again("000001", "owner1"))
+	_, info, _ = m.Look("owner1")
+	assert.Contains(info, "目前參與人數: 0/2")
+
+	assert.ErrorIs(m.Again("000001", "user1"), ErrRoundNotFound, "A player who left is a stranger again")
+	mustJoin(t, m, "000001", "user1", "User One", "")
+	assert.ErrorIs(m.Again("000001", "user1"), domain.ErrNotPermitted)
+	assert.ErrorIs(m.Again("000002", "owner1"), ErrRoundNotFound)
+}
+
+func TestRoundManager_Expire(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 1)))
+
+	m.Expire("owner1")
+	assert.False(t, m.HasInviteNo("000001"))
+	_, _, err := m.Look("owner1")
+	assert.ErrorIs(t, err, ErrRoundNotFound)
+
+	// Expiring a missing round is a no-op.
+	m.Expire("owner1")
+}
+
+func TestRoundManager_ConcurrentJoin(t *testing.T) {
+	const seats = 12
+	const joiners = 100
+
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", seats)))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	joined, full := 0, 0
+	for i := range joiners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := m.Join("000001", "user"+strconv.Itoa(i), "User", "url")
+			mu.Lock()
+			defer mu.Unlock()
+			switch res.Status {
+			case JoinJoined:
+				joined++
+			case JoinFull:
+				full++
+			default:
+				t.Errorf("unexpected join status: %v", res.Status)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, seats, joined, "Exactly every seat should be taken")
+	assert.Equal(t, joiners-seats, full, "Every other joiner should see a full round")
+	_, info, err := m.Look("owner1")
+	require.NoError(t, err)
+	assert.Contains(t, info, "目前參與人數: 12/12")
+}
+
+func TestRoundManager_ConcurrentMixed(t *testing.T) {
+	m := newTestManager(t)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		ownerID := "owner" + strconv.Itoa(i)
+		inviteNo := strconv.Itoa(100000 + i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = m.Create(newTestRound(ownerID, inviteNo, 5))
+			for j := range 5 {
+				_ = m.Join(inviteNo, ownerID+"-user"+strconv.Itoa(j), "User", "url")
+				_, _, _ = m.Look(ownerID)
+			}
+			_ = m.Again(inviteNo, ownerID)
+			m.Expire(ownerID)
+		}()
+	}
+	wg.Wait()
+
+	for i := range 20 {
+		assert.False(t, m.HasInviteNo(strconv.Itoa(100000+i)))
+	}
+}
+
+func TestRoundManager_Events(t *testing.T) {
+	m := newTestManager(t)
+	var got []RoundEventType
+	m.Subscribe(func(e RoundEvent) {
+		assert.Equal(t, "owner1", e.OwnerID)
+		assert.Equal(t, "000001", e.InviteNo)
+		got = append(got, e.Type)
+		// Handlers may call back into the manager without deadlocking.
+		_ = m.HasInviteNo(e.InviteNo)
+	})
+
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 2)))
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+	require.Equal(t, JoinJoined, m.Join("000001", "user2", "User Two", "url2").Status)
+	require.NoError(t, m.Again("000001", "owner1"))
+	m.Expire("owner1")
+	m.Expire("owner1")
+
+	assert.Equal(t, []RoundEventType{RoundCreated, RoundFilled, RoundReshuffled, RoundExpired}, got)
+}
+
+func TestRoundManager_RemoveExpired(t *testing.T) {
+	m := newTestManager(t)
+	expired := newTestRound("owner1", "000001", 1)
+	expired.ExpiredAt = time.Now().Add(-time.Minute)
+	require.NoError(t, m.Create(expired))
+	require.NoError(t, m.Create(newTestRound("owner2", "000002", 1)))
+
+	var expiredOwners []string
+	m.Subscribe(func(e RoundEvent) {
+		if e.Type == RoundExpired {
+			expiredOwners = append(expiredOwners, e.OwnerID)
+		}
+	})
+
+	assert.Equal(t, 1, m.RemoveExpired())
+	assert.False(t, m.HasInviteNo("000001"), "Expired round should be evicted")
+	assert.True(t, m.HasInviteNo("000002"), "Open round should be kept")
+	assert.Equal(t, []string{"owner1"}, expiredOwners)
+}
+
+func TestRoundManager_RunJanitor(t *testing.T) {
+	m := newTestManager(t)
+	r := newTestRound("owner1", "000001", 1)
+	r.ExpiredAt = time.Now().Add(-time.Minute)
+	require.NoError(t, m.Create(r))
+
+	evicted := make(chan struct{})
+	m.Subscribe(func(e RoundEvent) {
+		if e.Type == RoundExpired {
+			close(evicted)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.RunJanitor(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-evicted:
+	case <-time.After(time.Second):
+		t.Fatal("janitor did not evict the expired round")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("janitor did not stop after cancel")
+	}
+}
+
+func TestRoundManager_TeamReveals(t *testing.T) {
+	m := newTestManager(t)
+	r := domain.NewRound("owner1", "000001")
+	r.SetIdentity("owner1", domain.Werewolf, 2)
+	r.SetIdentity("owner1", domain.Villager, 1)
+	require.NoError(t, m.Create(r))
+
+	var filled []RoundEvent
+	m.Subscribe(func(e RoundEvent) {
+		if e.Type == RoundFilled {
+			filled = append(filled, e)
+		}
+	})
+	for i := range 3 {
+		require.Equal(t, JoinJoined, m.Join("000001", "user"+strconv.Itoa(i), "User", "url").Status)
+	}
+	require.Len(t, filled, 1)
+
+	reveals, err := m.TeamReveals(filled[0].InviteNo)
+	require.NoError(t, err)
+	require.Len(t, reveals, 2)
+	for _, r := range reveals {
+		require.Len(t, r.Teammates, 1)
+		assert.NotEqual(t, r.Wolf.UserID, r.Teammates[0].UserID)
+	}
+
+	_, err = m.TeamReveals("999999")
+	assert.ErrorIs(t, err, ErrRoundNotFound)
+}
+
+func TestRoundManager_FindByInviteNo(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 3)))
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+
+	summary, err := m.FindByInviteNo("000001")
+	require.NoError(t, err)
+	assert.Equal(t, RoundSummary{
+		OwnerID:      "owner1",
+		InviteNo:     "000001",
+		Identities:   []domain.Identity{domain.Villager, domain.Villager, domain.Villager},
+		Participants: 1,
+	}, summary)
+
+	_, err = m.FindByInviteNo("999999")
+	assert.ErrorIs(t, err, ErrRoundNotFound)
+}
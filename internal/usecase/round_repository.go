@@ -0,0 +1,13 @@
+package usecase
+
+import "werewolve-helper/internal/domain"
+
+// RoundRepository persists rounds so that open rooms survive a restart.
+type RoundRepository interface {
+	// Save inserts or replaces the round keyed by its owner ID.
+	Save(round *domain.Round) error
+	// Delete removes the owner's round. Deleting a missing round is not an error.
+	Delete(ownerID string) error
+	// FindAll returns every stored round.
+	FindAll() ([]*domain.Round, error)
+}
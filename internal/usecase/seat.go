@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"werewolve-helper/internal/domain"
+)
+
+// SeatView is the seat map of a round as players see it: who sits where, without identities.
+type SeatView struct {
+	InviteNo     string               // Invitation number of the round.
+	Seats        int                  // Number of seats, one per identity.
+	Participants []domain.Participant // Players who joined, ordered by seat, with their identity cleared.
+	GameStarted  bool                 // Whether seats are locked because the game engine started.
+	Owner        bool                 // Whether the viewer manages the round and may move anyone.
+}
+
+// Seats returns the seat map of the round with the given invite number. The hosts and the participants may see it;
+// to anyone else the round does not exist.
+func (m *RoundManager) Seats(inviteNo, userID string) (SeatView, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.memberRoundLocked(inviteNo, userID)
+	if !ok {
+		return SeatView{}, ErrRoundNotFound
+	}
+
+	participants := r.SeatedParticipants()
+	for i := range participants {
+		participants[i].Identity = 0
+	}
+	return SeatView{
+		InviteNo:     r.InviteNo,
+		Seats:        len(r.Identities),
+		Participants: participants,
+		GameStarted:  r.Game != nil,
+		Owner:        r.Can(userID, domain.PermManage),
+	}, nil
+}
+
+// MoveSeat moves the user to the seat of the round with the given invite number, on behalf of the requester.
+// See domain.Round.SetSeat for who may move whom.
+func (m *RoundManager) MoveSeat(inviteNo, requesterID, userID string, seat int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.memberRoundLocked(inviteNo, requesterID)
+	if !ok {
+		return ErrRoundNotFound
+	}
+	if err := r.SetSeat(requesterID, userID, seat); err != nil {
+		return err
+	}
+	m.saveLocked(r)
+	return nil
+}
+
+// RandomizeSeats shuffles the seats of the round with the given invite number on behalf of the owner.
+func (m *RoundManager) RandomizeSeats(inviteNo, ownerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.authorizeLocked(inviteNo, ownerID, domain.PermManage)
+	if err != nil {
+		return err
+	}
+	if err := r.RandomizeSeats(ownerID); err != nil {
+		return err
+	}
+	m.saveLocked(r)
+	return nil
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"testing"
+	"werewolve-helper/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundManager_Seats(t *testing.T) {
+	m := newTestManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 3)))
+	require.Equal(t, JoinJoined, m.Join("000001", "user1", "User One", "url1").Status)
+	require.Equal(t, JoinJoined, m.Join("000001", "user2", "User Two", "url2").Status)
+	assert := assert.New(t)
+
+	view, err := m.Seats("000001", "user2")
+	require.NoError(t, err)
+	assert.Equal(3, view.Seats)
+	assert.False(view.Owner)
+	require.Len(t, view.Participants, 2)
+	assert.Equal(1, view.Participants[0].Seat)
+	assert.Zero(view.Participants[0].Identity, "Players must not see identities on the seat map")
+
+	_, err = m.Seats("000001", "stranger")
+	assert.ErrorIs(err, ErrRoundNotFound, "Strangers cannot tell the round exists")
+	_, err = m.Seats("999999", "user1")
+	assert.ErrorIs(err, ErrRoundNotFound)
+
+	assert.ErrorIs(m.MoveSeat("000001", "user1", "user1", 2), domain.ErrSeatTaken)
+	assert.NoError(m.MoveSeat("000001", "user1", "user1", 3))
+	assert.NoError(m.MoveSeat("000001", "owner1", "user2", 3), "The owner swaps seats")
+
+	view, err = m.Seats("000001", "owner1")
+	require.NoError(t, err)
+	assert.True(view.Owner)
+	assert.Equal("user1", view.Participants[0].UserID)
+	assert.Equal(2, view.Participants[0].Seat)
+	assert.Equal("user2", view.Participants[1].UserID)
+
+	assert.ErrorIs(m.RandomizeSeats("000001", "user1"), domain.ErrNotPermitted)
+	assert.NoError(m.RandomizeSeats("000001", "owner1"))
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"errors"
+	"werewolve-helper/internal/domain"
+)
+
+// Errors returned by TemplateManager operations.
+var (
+	ErrTemplateNotFound  = errors.New("template not found")
+	ErrTooManyTemplates  = errors.New("too many templates")
+	ErrNoRoundToTemplate = errors.New("no round to save as template")
+)
+
+// MaxTemplatesPerOwner is the number of templates an owner can keep.
+const MaxTemplatesPerOwner = 20
+
+// TemplateManager saves owners' boards as templates and starts rounds from them.
+type TemplateManager struct {
+	repo   TemplateRepository
+	rounds *RoundManager
+}
+
+// NewTemplateManager creates a TemplateManager storing templates in repo and creating rounds through rounds.
+func NewTemplateManager(repo TemplateRepository, rounds *RoundManager) *TemplateManager {
+	return &TemplateManager{repo: repo, rounds: rounds}
+}
+
+// Save stores the board of the owner's current round under name, replacing a template of the same name.
+func (tm *TemplateManager) Save(ownerID, name string) (*domain.BoardTemplate, error) {
+	identities, rules, err := tm.rounds.Composition(ownerID)
+	if errors.Is(err, ErrRoundNotFound) {
+		return nil, ErrNoRoundToTemplate
+	}
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := domain.NewBoardTemplate(ownerID, name, identities, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := tm.repo.FindByOwner(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxTemplatesPerOwner && findTemplate(existing, tmpl.Name) == nil {
+		return nil, ErrTooManyTemplates
+	}
+	if err := tm.repo.Save(tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// List returns the owner's templates sorted by name.
+func (tm *TemplateManager) List(ownerID string) ([]*domain.BoardTemplate, error) {
+	return tm.repo.FindByOwner(ownerID)
+}
+
+// Delete removes the owner's template with the given name.
+func (tm *TemplateManager) Delete(ownerID, name string) error {
+	if _, err := tm.find(ownerID, name); err != nil {
+		return err
+	}
+	return tm.repo.Delete(ownerID, name)
+}
+
+// CreateRound starts a new round for the owner from the named template, replacing the owner's current round.
+func (tm *TemplateManager) CreateRound(ownerID, name string) (*domain.Round, error) {
+	tmpl, err := tm.find(ownerID, name)
+	if err != nil {
+		return nil, err
+	}
+	inviteNo, err := tm.rounds.NewInviteNo()
+	if err != nil {
+		return nil, err
+	}
+	r := tmpl.NewRound(inviteNo)
+	if err := tm.rounds.Create(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// find returns the owner's template with the given name.
+func (tm *TemplateManager) find(ownerID, name string) (*domain.BoardTemplate, error) {
+	templates, err := tm.repo.FindByOwner(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl := findTemplate(templates, name); tmpl != nil {
+		return tmpl, nil
+	}
+	return nil, ErrTemplateNotFound
+}
+
+// findTemplate returns the template with the given name, or nil if there is none.
+func findTemplate(templates []*domain.BoardTemplate, name string) *domain.BoardTemplate {
+	for _, t := range templates {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"strconv"
+	"testing"
+	"werewolve-helper/internal/adapter/storage"
+	"werewolve-helper/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTemplateManager(t *testing.T) (*TemplateManager, *RoundManager) {
+	t.Helper()
+	m := newTestManager(t)
+	return NewTemplateManager(storage.NewMemoryTemplateRepository(), m), m
+}
+
+func TestTemplateManager_SaveAndCreateRound(t *testing.T) {
+	assert := assert.New(t)
+	tm, m := newTestTemplateManager(t)
+
+	_, err := tm.Save("owner1", "board")
+	require.ErrorIs(t, err, ErrNoRoundToTemplate)
+
+	r := domain.NewRound("owner1", "000001")
+	r.SetIdentity("owner1", domain.Werewolf, 1)
+	r.SetIdentity("owner1", domain.Seer, 1)
+	r.SetIdentity("owner1", domain.Villager, 2)
+	r.Rules.WinRule = domain.WinAllKill
+	require.NoError(t, m.Create(r))
+
+	tmpl, err := tm.Save("owner1", "週五團")
+	require.NoError(t, err)
+	assert.Equal("1狼 1神 2民", tmpl.Summary())
+
+	_, err = tm.Save("owner1", "")
+	require.ErrorIs(t, err, domain.ErrTemplateNameInvalid)
+
+	templates, err := tm.List("owner1")
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal("週五團", templates[0].Name)
+
+	created, err := tm.CreateRound("owner1", "週五團")
+	require.NoError(t, err)
+	assert.ElementsMatch(tmpl.Identities, created.Identities)
+	assert.Equal(domain.WinAllKill, created.Rules.WinRule)
+	assert.True(m.HasInviteNo(created.InviteNo))
+	assert.False(m.HasInviteNo("000001"), "The new round replaces the owner's old one")
+
+	_, err = tm.CreateRound("owner1", "missing")
+	assert.ErrorIs(err, ErrTemplateNotFound)
+	_, err = tm.CreateRound("owner2", "週五團")
+	assert.ErrorIs(err, ErrTemplateNotFound, "Templates belong to their owner")
+}
+
+func TestTemplateManager_Delete(t *testing.T) {
+	tm, m := newTestTemplateManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 3)))
+	_, err := tm.Save("owner1", "board")
+	require.NoError(t, err)
+
+	require.NoError(t, tm.Delete("owner1", "board"))
+	assert.ErrorIs(t, tm.Delete("owner1", "board"), ErrTemplateNotFound)
+
+	templates, err := tm.List("owner1")
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
+
+func TestTemplateManager_Limit(t *testing.T) {
+	tm, m := newTestTemplateManager(t)
+	require.NoError(t, m.Create(newTestRound("owner1", "000001", 3)))
+
+	for i := range MaxTemplatesPerOwner {
+		_, err := tm.Save("owner1", "board"+strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+
+	_, err := tm.Save("owner1", "one too many")
+	require.ErrorIs(t, err, ErrTooManyTemplates)
+	_, err = tm.Save("owner1", "board0")
+	assert.NoError(t, err, "Replacing an existing template is always allowed")
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import "werewolve-helper/internal/domain"
+
+// TemplateRepository persists the board templates saved by room owners.
+type TemplateRepository interface {
+	// Save inserts or replaces the template keyed by its owner ID and name.
+	Save(tmpl *domain.BoardTemplate) error
+	// Delete removes the owner's template with the given name. Deleting a missing template is not an error.
+	Delete(ownerID, name string) error
+	// FindByOwner returns the owner's templates sorted by name.
+	FindByOwner(ownerID string) ([]*domain.BoardTemplate, error)
+}